@@ -0,0 +1,141 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+)
+
+type money struct {
+	cents int
+}
+
+func (m money) FluentValue() fluent.Value {
+	return fluent.NumberLiteral(float32(m.cents) / 100)
+}
+
+type planName string
+
+type status int
+
+func (s *status) String() string {
+	if *s == 1 {
+		return "active"
+	}
+	return "inactive"
+}
+
+type audit struct {
+	Author string `word:"author"`
+}
+
+type orderArgs struct {
+	audit
+	Name    string `word:"name"`
+	Count   int    `word:"count"`
+	Total   money  `word:"total"`
+	Plan    planName
+	Status  *status `word:"status"`
+	Comment *string `word:"comment"`
+	Secret  string  `word:"-"`
+	ignored string
+}
+
+func TestClient_TATypedArgs(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "order = { $name } ordered { $count } for { $total } on { $Plan }, { $status }\nsecret = [{ $Secret }]\n",
+	})
+	defer c.Close(context.Background())
+
+	active := status(1)
+	args := orderArgs{
+		Name:   "Olena",
+		Count:  3,
+		Total:  money{cents: 1250},
+		Plan:   "pro",
+		Status: &active,
+		Secret: "hidden",
+	}
+	want := "Olena ordered 3 for 12.5 on pro, active"
+
+	if got := c.TA("en_US", "order", args); got != want {
+		t.Errorf("TA(struct) = %q, want %q", got, want)
+	}
+	if got := c.TA("en_US", "order", &args); got != want {
+		t.Errorf("TA(*struct) = %q, want %q", got, want)
+	}
+	if got := c.Dynamic().TA("en_US", "order", args); got != want {
+		t.Errorf("Dynamic().TA(struct) = %q, want %q", got, want)
+	}
+	if got := c.TA("en_US", "secret", args); got == "[hidden]" {
+		t.Errorf("TA() used a field tagged word:\"-\"")
+	}
+}
+
+func TestClient_TATypedNil(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "total = Total: { $total }\n",
+	})
+	defer c.Close(context.Background())
+
+	// Typed nils are skipped like nil, without calling their methods.
+	want := c.TA("en_US", "total", map[string]any{"total": nil})
+	var m *money
+	var s *fluent.StringValue
+	var n *fluent.NumberValue
+	for _, value := range []any{m, s, n, &m} {
+		if got := c.TA("en_US", "total", map[string]any{"total": value}); got != want {
+			t.Errorf("TA(%T(nil)) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestWithArgs_Diagnostics(t *testing.T) {
+	bundle := fluent.NewBundle("en_US")
+	resource, parseErrs := fluent.NewResource("hello = Hello, { $name }!\n")
+	if parseErrs != nil {
+		t.Fatalf("NewResource() errors = %v", parseErrs)
+	}
+	bundle.AddResource(resource)
+
+	_, errs, err := bundle.FormatMessage("hello", fluent.WithArgs(map[string]any{"name": []int{1}}))
+	if err != nil {
+		t.Fatalf("FormatMessage() error = %v", err)
+	}
+	if !containsError(errs, fluent.ErrUnsupportedValue) {
+		t.Errorf("FormatMessage() errors = %v, want %v", errs, fluent.ErrUnsupportedValue)
+	}
+
+	_, errs, _ = bundle.FormatMessage("hello", fluent.WithArgs(42))
+	if !containsError(errs, fluent.ErrUnsupportedValue) {
+		t.Errorf("FormatMessage(WithArgs(42)) errors = %v, want %v", errs, fluent.ErrUnsupportedValue)
+	}
+
+	got, errs, _ := bundle.FormatMessage("hello", fluent.WithArgs(map[string]string{"name": "Olena"}))
+	if got != "Hello, Olena!" || len(errs) > 0 {
+		t.Errorf("FormatMessage(map[string]string) = %q, %v", got, errs)
+	}
+}
+
+func TestDynamicContent_TAUnsupportedArgs(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	// Used to panic on the type assertion to map[string]any.
+	if got := c.Dynamic().TA("en_US", "hello", 42); got != "Hello" {
+		t.Errorf("Dynamic().TA() = %q, want %q", got, "Hello")
+	}
+}
+
+func containsError(errs []error, target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
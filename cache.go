@@ -0,0 +1,278 @@
+package word
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// CacheStats reports the memory held by the client's catalog and the work done
+// to keep it within Config.MaxCacheSizeMB and Config.MaxDynamicEntries.
+type CacheStats struct {
+	// Bytes is the estimated memory of the catalog: BundleBytes + ValueBytes.
+	Bytes int
+	// BundleBytes is the estimated memory of the loaded bundles' ASTs,
+	// those of the synced dynamic values included.
+	BundleBytes int
+	// ValueBytes is the memory of the raw values bundles are rebuilt from.
+	ValueBytes int
+	// LimitBytes is the configured limit, 0 if unlimited.
+	LimitBytes int
+
+	Locales       int
+	LoadedLocales int
+	// Evictions counts bundles evicted to stay within LimitBytes and
+	// Reloads the evicted bundles rebuilt on demand.
+	Evictions uint64
+	Reloads   uint64
+
+	// DynamicEntries is the number of values saved through DynamicContent
+	// and DynamicDropped the number dropped to stay within MaxDynamicEntries.
+	DynamicEntries int
+	DynamicDropped uint64
+}
+
+// bundleCache holds the eviction policy and counters shared by the catalogs
+// of one client.
+type bundleCache struct {
+	maxBytes int64
+	current  func() *catalog
+	metrics  Metrics
+	// pseudo is Config.PseudoLocale, built by catalogs from pseudoSource.
+	pseudo       cldr.Language
+	pseudoSource cldr.Language
+
+	evictions      atomic.Uint64
+	reloads        atomic.Uint64
+	dynamicDropped atomic.Uint64
+
+	// enforcing is held while the limit is enforced, so concurrent reloads
+	// don't evict the same bundles twice.
+	enforcing sync.Mutex
+}
+
+// localeEntry holds the values of one locale and the bundle built from them.
+// The bundle is dropped when evicted and rebuilt from the values on next use.
+type localeEntry struct {
+	lang       cldr.Language
+	static     map[string]string
+	saved      map[string]string
+	transform  fluent.TextTransform
+	valueBytes int64
+	cache      *bundleCache
+
+	mu       sync.Mutex // serializes rebuilds
+	bundle   atomic.Pointer[fluent.Bundle]
+	size     atomic.Int64
+	lastUsed atomic.Int64
+}
+
+func newLocaleEntry(cache *bundleCache, lang cldr.Language, static, saved map[string]string, bundle *fluent.Bundle) *localeEntry {
+	e := &localeEntry{
+		lang:   lang,
+		static: static,
+		saved:  saved,
+		cache:  cache,
+	}
+	for key, value := range static {
+		e.valueBytes += int64(len(key) + len(value))
+	}
+	for key, value := range saved {
+		e.valueBytes += int64(len(key) + len(value))
+	}
+	e.bundle.Store(bundle)
+	e.size.Store(int64(bundle.Size()))
+	e.lastUsed.Store(time.Now().UnixNano())
+	cache.metrics.BundleSize(lang, int(e.size.Load()))
+	return e
+}
+
+// load returns the bundle, rebuilding it if it was evicted.
+func (e *localeEntry) load() *fluent.Bundle {
+	e.lastUsed.Store(time.Now().UnixNano())
+	if bundle := e.bundle.Load(); bundle != nil {
+		return bundle
+	}
+
+	e.mu.Lock()
+	bundle := e.bundle.Load()
+	if bundle == nil {
+		// The values were parsed when the entry was created, so this can't fail.
+		bundle, _ = buildBundle(e.lang, e.static, e.saved)
+		if bundle == nil {
+			e.mu.Unlock()
+			return nil
+		}
+		bundle.SetTextTransform(e.transform)
+		e.bundle.Store(bundle)
+		e.size.Store(int64(bundle.Size()))
+		e.cache.reloads.Add(1)
+		e.cache.metrics.BundleSize(e.lang, int(e.size.Load()))
+	}
+	e.mu.Unlock()
+
+	e.cache.enforce()
+	return bundle
+}
+
+// evict drops the bundle and reports whether there was one to drop.
+func (e *localeEntry) evict() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.bundle.Load() == nil {
+		return false
+	}
+	e.bundle.Store(nil)
+	e.size.Store(0)
+	return true
+}
+
+// enforce evicts the least recently used bundles of the current catalog until
+// it fits within maxBytes. The most recently used bundle is always kept, so a
+// single locale larger than the limit doesn't get rebuilt on every lookup.
+func (bc *bundleCache) enforce() {
+	if bc.maxBytes <= 0 || bc.current == nil {
+		return
+	}
+	if !bc.enforcing.TryLock() {
+		return
+	}
+	defer bc.enforcing.Unlock()
+
+	cat := bc.current()
+	if cat == nil {
+		return
+	}
+
+	// The bundles of synced dynamic values can't be rebuilt, so they are
+	// never evicted, but they count toward the limit.
+	var (
+		total  = cat.syncedBytes
+		loaded []*localeEntry
+	)
+	for _, e := range cat.entries {
+		total += e.valueBytes + e.size.Load()
+		if e.bundle.Load() != nil {
+			loaded = append(loaded, e)
+		}
+	}
+	if total <= bc.maxBytes {
+		return
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].lastUsed.Load() < loaded[j].lastUsed.Load()
+	})
+	for _, e := range loaded[:max(len(loaded)-1, 0)] {
+		if total <= bc.maxBytes {
+			return
+		}
+		size := e.size.Load()
+		if e.evict() {
+			total -= size
+			bc.evictions.Add(1)
+			bc.metrics.BundleSize(e.lang, 0)
+		}
+	}
+}
+
+// CacheStats returns the current memory accounting of the catalog.
+func (c *Client) CacheStats() CacheStats {
+	cat := c.catalog()
+	stats := CacheStats{
+		LimitBytes:     int(c.cache.maxBytes),
+		BundleBytes:    int(cat.syncedBytes),
+		Locales:        len(cat.entries),
+		Evictions:      c.cache.evictions.Load(),
+		Reloads:        c.cache.reloads.Load(),
+		DynamicDropped: c.cache.dynamicDropped.Load(),
+	}
+	for _, e := range cat.entries {
+		stats.BundleBytes += int(e.size.Load())
+		stats.ValueBytes += int(e.valueBytes)
+		if e.bundle.Load() != nil {
+			stats.LoadedLocales++
+		}
+	}
+	for _, values := range cat.saved {
+		stats.DynamicEntries += len(values)
+	}
+	stats.Bytes = stats.BundleBytes + stats.ValueBytes
+	return stats
+}
+
+// GetCacheSize returns the estimated memory of the catalog in bytes.
+func (c *Client) GetCacheSize() int {
+	return c.CacheStats().Bytes
+}
+
+type savedKey struct {
+	lang cldr.Language
+	key  string
+}
+
+type savedItem struct {
+	savedKey
+	seq uint64
+}
+
+// capSaved records the keys of added as the most recently saved and drops the
+// oldest values beyond MaxDynamicEntries from saved. It returns the locales
+// that lost values. It must be called with writeMu held.
+func (c *Client) capSaved(saved, added catalogValues) []cldr.Language {
+	if c.maxDynamicEntries <= 0 {
+		return nil
+	}
+	if c.savedSeqs == nil {
+		c.savedSeqs = make(map[savedKey]uint64)
+	}
+
+	for lang, values := range added {
+		for key := range values {
+			c.savedSeq++
+			k := savedKey{lang, key}
+			c.savedSeqs[k] = c.savedSeq
+			c.savedQueue = append(c.savedQueue, savedItem{k, c.savedSeq})
+		}
+	}
+
+	var dropped []cldr.Language
+	for len(c.savedSeqs) > c.maxDynamicEntries && len(c.savedQueue) > 0 {
+		item := c.savedQueue[0]
+		c.savedQueue = c.savedQueue[1:]
+		// Keys saved again have a newer item further down the queue.
+		if c.savedSeqs[item.savedKey] != item.seq {
+			continue
+		}
+		delete(c.savedSeqs, item.savedKey)
+		delete(saved[item.lang], item.key)
+		if len(saved[item.lang]) == 0 {
+			delete(saved, item.lang)
+		}
+		dropped = append(dropped, item.lang)
+		c.cache.dynamicDropped.Add(1)
+	}
+
+	// Drop the stale items left behind by keys saved more than once.
+	if len(c.savedQueue) > 2*len(c.savedSeqs) {
+		queue := make([]savedItem, 0, len(c.savedSeqs))
+		for _, item := range c.savedQueue {
+			if c.savedSeqs[item.savedKey] == item.seq {
+				queue = append(queue, item)
+			}
+		}
+		c.savedQueue = queue
+	}
+
+	return dropped
+}
+
+// resetSaved forgets the order of saved values. It must be called with writeMu held.
+func (c *Client) resetSaved() {
+	c.savedSeqs = nil
+	c.savedQueue = nil
+}
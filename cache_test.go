@@ -0,0 +1,127 @@
+package word
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClient_CacheEvictsLeastUsedLocale(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "hello = Hello\n",
+		"uk_UA": "hello = Привіт\n",
+		"de_DE": "hello = Hallo\n",
+	})
+	defer c.Close(context.Background())
+
+	stats := c.CacheStats()
+	if stats.Locales != 3 || stats.LoadedLocales != 3 {
+		t.Fatalf("CacheStats() = %+v, want 3 loaded locales", stats)
+	}
+	if stats.Bytes <= stats.ValueBytes {
+		t.Fatalf("CacheStats().Bytes = %d, want more than the values (%d)", stats.Bytes, stats.ValueBytes)
+	}
+
+	// Leave room for the values and a single bundle.
+	perBundle := stats.BundleBytes / stats.Locales
+	c.cache.maxBytes = int64(stats.ValueBytes + perBundle + perBundle/2)
+
+	c.T("de_DE", "hello")
+	c.T("uk_UA", "hello")
+	c.cache.enforce()
+
+	stats = c.CacheStats()
+	if stats.LoadedLocales != 1 || stats.Evictions != 2 {
+		t.Fatalf("CacheStats() = %+v, want 1 loaded locale and 2 evictions", stats)
+	}
+	if stats.Bytes > int(c.cache.maxBytes) {
+		t.Errorf("CacheStats().Bytes = %d, want at most %d", stats.Bytes, c.cache.maxBytes)
+	}
+
+	// Evicted locales are rebuilt on their next lookup.
+	if got := c.T("en_US", "hello"); got != "Hello" {
+		t.Errorf("T(en_US) = %q, want %q", got, "Hello")
+	}
+	stats = c.CacheStats()
+	if stats.Reloads != 1 || stats.LoadedLocales != 1 {
+		t.Errorf("CacheStats() = %+v, want 1 reload and 1 loaded locale", stats)
+	}
+}
+
+func TestClient_CacheEvictsLocalizerLocale(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "hello = Hello\n",
+		"uk_UA": "hello = Привіт\n",
+	})
+	defer c.Close(context.Background())
+
+	l, err := c.Localizer("en_US")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+
+	stats := c.CacheStats()
+	c.cache.maxBytes = int64(stats.ValueBytes + stats.BundleBytes/stats.Locales)
+	c.T("uk_UA", "hello")
+	c.cache.enforce()
+	if stats = c.CacheStats(); stats.Evictions != 1 {
+		t.Fatalf("CacheStats() = %+v, want 1 eviction", stats)
+	}
+
+	// The Localizer doesn't hold on to the evicted bundle: it is rebuilt.
+	if got := l.T("hello"); got != "Hello" {
+		t.Errorf("T(hello) = %q, want %q", got, "Hello")
+	}
+	if stats = c.CacheStats(); stats.Reloads != 1 {
+		t.Errorf("CacheStats() = %+v, want 1 reload", stats)
+	}
+}
+
+func TestClient_CacheKeepsLocaleLargerThanLimit(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	c.cache.maxBytes = 1
+	for i := 0; i < 3; i++ {
+		if got := c.T("en_US", "hello"); got != "Hello" {
+			t.Fatalf("T() = %q, want %q", got, "Hello")
+		}
+	}
+	if stats := c.CacheStats(); stats.Evictions != 0 || stats.Reloads != 0 {
+		t.Errorf("CacheStats() = %+v, want no evictions", stats)
+	}
+}
+
+func TestClient_MaxDynamicEntries(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{MaxDynamicEntries: 2}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	d := c.Dynamic()
+	for _, key := range []string{"a", "b", "a", "c"} {
+		if err := d.SaveTranslation("en_US", key, "Value "+key); err != nil {
+			t.Fatalf("SaveTranslation(%s) error = %v", key, err)
+		}
+	}
+
+	// b is the oldest value once a has been saved again.
+	cat := c.catalog()
+	if _, ok := cat.saved["en_US"]["b"]; ok {
+		t.Errorf("saved values = %v, want b dropped", cat.saved["en_US"])
+	}
+	for _, key := range []string{"a", "c"} {
+		if got := c.T("en_US", key); got != "Value "+key {
+			t.Errorf("T(%s) = %q, want %q", key, got, "Value "+key)
+		}
+	}
+	if got := c.T("en_US", "hello"); got != "Hello" {
+		t.Errorf("T(hello) = %q, want static values kept", got)
+	}
+
+	stats := c.CacheStats()
+	if stats.DynamicEntries != 2 || stats.DynamicDropped != 1 {
+		t.Errorf("CacheStats() = %+v, want 2 dynamic entries and 1 dropped", stats)
+	}
+}
@@ -0,0 +1,254 @@
+package word
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// catalog is an immutable set of bundles together with the values they were
+// built from. Readers load the current catalog once per lookup and never see a
+// partially applied update; writers build a new catalog and swap it in.
+// The bundle of a locale may be evicted to save memory, in which case it is
+// rebuilt from the locale's values on next use; see cache.go.
+type catalog struct {
+	entries map[cldr.Language]*localeEntry
+	// static holds the values loaded from the source.
+	static catalogValues
+	// dynamic holds the dynamic values synced from the source with
+	// Config.SyncDynamicContent.
+	dynamic catalogValues
+	// saved holds the values saved through DynamicContent since the last Reset.
+	// They take precedence over static values.
+	saved catalogValues
+	// synced holds the bundles of the dynamic values, which DynamicContent
+	// looks up after the bundle of their locale. Values that bundle already
+	// has are left out, so sources whose dynamic values are their static ones
+	// aren't held twice. syncedBytes is their estimated memory.
+	synced      map[cldr.Language]*fluent.Bundle
+	syncedBytes int64
+	// revisions and dynamicRevisions hold the revisions of the static and
+	// synced dynamic values of every namespace. Only the catalog of the
+	// shared namespace has them; they are carried over from prev.
+	revisions        revisions
+	dynamicRevisions revisions
+}
+
+// bundle returns the bundle of lang, or nil if the catalog has none.
+func (cat *catalog) bundle(lang cldr.Language) *fluent.Bundle {
+	entry := cat.entries[lang]
+	if entry == nil {
+		return nil
+	}
+	return entry.load()
+}
+
+// syncedBundle returns the bundle of the synced dynamic values of lang, or nil
+// if the catalog has none.
+func (cat *catalog) syncedBundle(lang cldr.Language) *fluent.Bundle {
+	return cat.synced[lang]
+}
+
+// locales returns the locales the catalog has bundles for.
+func (cat *catalog) locales() []cldr.Language {
+	locales := make([]cldr.Language, 0, len(cat.entries))
+	for l := range cat.entries {
+		locales = append(locales, l)
+	}
+	return locales
+}
+
+// newCatalog builds a catalog from static values overlaid with saved values,
+// and the bundles of the synced dynamic values beside them.
+// Only the locales in rebuild get new bundles, the others are shared with prev,
+// which is safe because bundles are never modified once published.
+// A nil rebuild set rebuilds every locale.
+func newCatalog(cache *bundleCache, prev *catalog, static, dynamic, saved catalogValues, rebuild map[cldr.Language]struct{}) (*catalog, error) {
+	cat := &catalog{
+		entries: make(map[cldr.Language]*localeEntry),
+		static:  static,
+		dynamic: dynamic,
+		saved:   saved,
+		synced:  make(map[cldr.Language]*fluent.Bundle),
+	}
+	if prev != nil {
+		cat.revisions, cat.dynamicRevisions = prev.revisions, prev.dynamicRevisions
+	}
+
+	locales := make(map[cldr.Language]struct{})
+	for l := range static {
+		locales[l] = struct{}{}
+	}
+	for l := range saved {
+		locales[l] = struct{}{}
+	}
+
+	for l := range locales {
+		if _, ok := rebuild[l]; rebuild != nil && !ok && prev != nil {
+			if entry := prev.entries[l]; entry != nil {
+				cat.entries[l] = entry
+				continue
+			}
+		}
+
+		bundle, err := buildBundle(l, static[l], saved[l])
+		if err != nil {
+			return nil, err
+		}
+		if bundle != nil {
+			cat.entries[l] = newLocaleEntry(cache, l, static[l], saved[l], bundle)
+		}
+	}
+
+	for l, values := range dynamic {
+		if _, ok := rebuild[l]; rebuild != nil && !ok && prev != nil {
+			if bundle := prev.synced[l]; bundle != nil {
+				cat.synced[l] = bundle
+				cat.syncedBytes += int64(bundle.Size())
+			}
+			continue
+		}
+
+		// Dynamic values are formatted like saved ones.
+		bundle, err := buildBundle(l, nil, visibleDynamic(values, static[l], saved[l]))
+		if err != nil {
+			return nil, err
+		}
+		if bundle != nil {
+			cat.synced[l] = bundle
+			cat.syncedBytes += int64(bundle.Size())
+		}
+	}
+
+	// The pseudo locale is a pseudo-localized copy of its source locale.
+	if p, src := cache.pseudo, cache.pseudoSource; p != "" && cat.entries[src] != nil {
+		if _, ok := rebuild[src]; rebuild != nil && !ok && prev != nil && prev.entries[p] != nil {
+			cat.entries[p] = prev.entries[p]
+		} else {
+			// The values were parsed for the source locale, so this can't fail.
+			bundle, _ := buildBundle(p, static[src], saved[src])
+			bundle.SetTextTransform(fluent.PseudoText)
+			entry := newLocaleEntry(cache, p, static[src], saved[src], bundle)
+			entry.transform = fluent.PseudoText
+			cat.entries[p] = entry
+		}
+	}
+
+	return cat, nil
+}
+
+// visibleDynamic returns the dynamic values whose keys neither static nor
+// saved have; the others are never looked up. It returns dynamic itself if
+// all are kept.
+func visibleDynamic(dynamic, static, saved map[string]string) map[string]string {
+	visible := make(map[string]string, len(dynamic))
+	for key, value := range dynamic {
+		if _, ok := static[key]; ok {
+			continue
+		}
+		if _, ok := saved[key]; ok {
+			continue
+		}
+		visible[key] = value
+	}
+	if len(visible) == len(dynamic) {
+		return dynamic
+	}
+	return visible
+}
+
+// buildBundle creates the bundle of lang. It returns nil if there is nothing to add.
+func buildBundle(lang cldr.Language, static, saved map[string]string) (*fluent.Bundle, error) {
+	if len(static) == 0 && len(saved) == 0 {
+		return nil, nil
+	}
+
+	bundle := fluent.NewBundle(lang)
+	for key, value := range static {
+		if _, ok := saved[key]; ok {
+			continue
+		}
+		resource, errs := fluent.NewResource(staticEntry(key, value))
+		if errs != nil {
+			return nil, fmt.Errorf("failed to create resource for language %s: %v", lang, errs)
+		}
+		if err := bundle.AddResource(resource); err != nil {
+			return nil, fmt.Errorf("failed to add resource for language %s: %v", lang, err)
+		}
+	}
+	for key, value := range saved {
+		resource, errs := fluent.NewResource(source.FormatFTLEntry(key, value))
+		if errs != nil {
+			return nil, fmt.Errorf("failed to create resource for language %s: %v", lang, errs)
+		}
+		bundle.AddResourceOverriding(resource)
+	}
+
+	return bundle, nil
+}
+
+// staticEntry formats a value loaded by the source as an FTL entry. Values of
+// multi-line messages keep the indentation they had in the source.
+func staticEntry(key, value string) string {
+	var sb strings.Builder
+	sb.WriteString(key)
+	sb.WriteString(" = ")
+	if len(value) == 0 {
+		sb.WriteString(` `)
+	}
+	sb.WriteString(value)
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// catalog returns the catalog currently served.
+func (c *Client) catalog() *catalog {
+	return c.current.Load()
+}
+
+// updateCatalog builds a new catalog from the current one with fn and publishes
+// it. Updates are serialized, so fn always sees the latest catalog.
+func (c *Client) updateCatalog(fn func(cur *catalog) (*catalog, error)) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	next, err := fn(c.catalog())
+	if err != nil {
+		return err
+	}
+	c.current.Store(next)
+	c.cache.enforce()
+	return nil
+}
+
+// merge returns a copy of v with the values of other added or replaced.
+func (v catalogValues) merge(other catalogValues) catalogValues {
+	result := make(catalogValues, len(v))
+	for l, values := range v {
+		result[l] = make(map[string]string, len(values))
+		for k, value := range values {
+			result[l][k] = value
+		}
+	}
+	for l, values := range other {
+		if result[l] == nil {
+			result[l] = make(map[string]string, len(values))
+		}
+		for k, value := range values {
+			result[l][k] = value
+		}
+	}
+	return result
+}
+
+// localeSet returns the locales of v.
+func (v catalogValues) localeSet() map[cldr.Language]struct{} {
+	set := make(map[cldr.Language]struct{}, len(v))
+	for l := range v {
+		set[l] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,83 @@
+package word
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestClient_SyncRemovesDeletedKeys(t *testing.T) {
+	c, paths := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "kept = Kept\nremoved = Removed\n",
+	})
+	defer c.Close(context.Background())
+
+	if err := c.Dynamic().SaveTranslation("en_US", "saved", "Saved"); err != nil {
+		t.Fatalf("SaveTranslation() error = %v", err)
+	}
+
+	if err := os.WriteFile(paths["en_US"], []byte("kept = Kept again\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	c.syncTranslations(context.Background())
+
+	if got := c.T("en_US", "removed"); got != "removed" {
+		t.Errorf("T(removed) = %q, want the key", got)
+	}
+	if got := c.T("en_US", "kept"); got != "Kept again" {
+		t.Errorf("T(kept) = %q, want %q", got, "Kept again")
+	}
+	// Values saved through DynamicContent survive a sync.
+	if got := c.T("en_US", "saved"); got != "Saved" {
+		t.Errorf("T(saved) = %q, want %q", got, "Saved")
+	}
+}
+
+func TestClient_SyncDuringLookups(t *testing.T) {
+	c, paths := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "a = A0\nb = B0\n",
+	})
+	defer c.Close(context.Background())
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// Both keys always come from the same catalog version.
+				a, b := c.catalogPair()
+				if a[1:] != b[1:] {
+					t.Errorf("inconsistent catalog: a = %q, b = %q", a, b)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 20; i++ {
+		content := fmt.Sprintf("a = A%d\nb = B%d\n", i, i)
+		if err := os.WriteFile(paths["en_US"], []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+		c.syncTranslations(context.Background())
+	}
+	close(stop)
+	wg.Wait()
+}
+
+// catalogPair formats a and b from one catalog snapshot.
+func (c *Client) catalogPair() (string, string) {
+	bundle := c.catalog().bundle("en_US")
+	a, _, _ := bundle.FormatMessage("a")
+	b, _, _ := bundle.FormatMessage("b")
+	return a, b
+}
@@ -24,17 +24,7 @@ var exportCmd = &cobra.Command{
 			return fmt.Errorf("api-key is required")
 		}
 
-		var apiURL string
-		switch env {
-		case "production":
-			apiURL = "https://wordapi.thesumm.it/api/v1"
-		case "stage":
-			apiURL = "https://dev.wordapi.thesumm.it/api/v1"
-		default:
-			apiURL = env
-		}
-
-		src := source.NewRemote(apiURL, apiKey)
+		src := source.NewRemote(apiURL(env), apiKey)
 
 		var data []source.Object
 		var err error
@@ -62,6 +52,18 @@ var exportCmd = &cobra.Command{
 	},
 }
 
+// apiURL returns the base URL of the API environment env, which may also be a URL.
+func apiURL(env string) string {
+	switch env {
+	case "production":
+		return "https://wordapi.thesumm.it/api/v1"
+	case "stage":
+		return "https://dev.wordapi.thesumm.it/api/v1"
+	default:
+		return env
+	}
+}
+
 func exportObjectsToFTLFiles(objects []source.Object, outDir string, path string) error {
 	groups := make(map[string][]source.Object)
 	for _, obj := range objects {
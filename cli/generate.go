@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/summit-fi/wordsdk-go/codegen"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate typed Go accessors for translation messages",
+	Long: `Generate reads FTL translations, either local files given with --ftl or the
+static translations of the API, and writes a Go file with a constant per message
+key and a typed function per message.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey, _ := cmd.Flags().GetString("api-key")
+		env, _ := cmd.Flags().GetString("environment")
+		files, _ := cmd.Flags().GetStringSlice("ftl")
+		pkg, _ := cmd.Flags().GetString("package")
+		out, _ := cmd.Flags().GetString("file")
+		docLocale, _ := cmd.Flags().GetString("doc-locale")
+
+		var src source.Source
+		if len(files) > 0 {
+			paths, err := ftlPaths(files)
+			if err != nil {
+				return err
+			}
+			ftl := source.NewFtl()
+			if err := ftl.AddLocaleFiles(paths); err != nil {
+				return fmt.Errorf("failed to add FTL files: %w", err)
+			}
+			src = ftl
+		} else {
+			if apiKey == "" {
+				return fmt.Errorf("api-key or ftl is required")
+			}
+			src = source.NewRemote(apiURL(env), apiKey)
+		}
+
+		data, _, err := src.LoadAllStatic("")
+		if err != nil {
+			return fmt.Errorf("failed to load static translations: %w", err)
+		}
+
+		code, err := codegen.Generate(data, codegen.Options{Package: pkg, DocLocale: docLocale})
+		if err != nil {
+			return fmt.Errorf("failed to generate code: %w", err)
+		}
+
+		if out == "" {
+			out = filepath.Join(pkg, pkg+".go")
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(out, code, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Generated %s\n", out)
+		return nil
+	},
+}
+
+func init() {
+	generateCmd.Flags().StringSlice("ftl", nil, "FTL files as locale=path, or directories of <locale>.ftl files")
+	generateCmd.Flags().String("package", "msgs", "Name of the generated package")
+	generateCmd.Flags().String("file", "", "Generated file (default <package>/<package>.go)")
+	generateCmd.Flags().String("doc-locale", "", "Locale whose values document the generated functions (default en_US)")
+}
+
+// ftlPaths maps locales to FTL files from locale=path pairs and directories
+// holding <locale>.ftl files.
+func ftlPaths(args []string) (map[string]string, error) {
+	paths := make(map[string]string)
+	for _, arg := range args {
+		if locale, path, ok := strings.Cut(arg, "="); ok {
+			paths[locale] = path
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(arg, "*.ftl"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no FTL files in %s", arg)
+		}
+		for _, path := range matches {
+			paths[strings.TrimSuffix(filepath.Base(path), ".ftl")] = path
+		}
+	}
+	return paths, nil
+}
@@ -4,7 +4,7 @@ go 1.25.0
 
 require (
 	github.com/spf13/cobra v1.10.2
-	github.com/summit-fi/wordsdk-go v0.0.5
+	github.com/summit-fi/wordsdk-go v0.0.6
 )
 
 require (
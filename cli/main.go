@@ -8,6 +8,7 @@ import (
 
 func main() {
 	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(generateCmd)
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -1,14 +1,16 @@
 package word
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"reflect"
-	"strings"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/summit-fi/wordsdk-go/fluent"
 	"github.com/summit-fi/wordsdk-go/fluent/cldr"
 
 	"github.com/summit-fi/wordsdk-go/source"
@@ -17,8 +19,69 @@ import (
 type Config struct {
 	Source         source.Source
 	UpdateInterval time.Duration
+	// MaxBackoff caps the delay between syncs after failures. Failed syncs are
+	// retried with exponential backoff starting at UpdateInterval.
+	// Defaults to 5 minutes.
+	MaxBackoff time.Duration
+	// MaxCacheSizeMB limits the estimated memory of the catalog. When exceeded,
+	// the bundles of the least recently used locales are evicted and rebuilt
+	// on their next lookup. Zero means unlimited.
 	MaxCacheSizeMB int
 	SaveStrategy   SaveStrategy
+	// MaxDynamicEntries caps the values saved through DynamicContent that are
+	// kept in memory; the oldest are dropped first. Zero means unlimited.
+	MaxDynamicEntries int
+
+	// Fallbacks maps a locale to the locales tried, in order, when its own bundle
+	// lacks a message, e.g. uk_UA -> [ru_UA, en_US].
+	Fallbacks map[cldr.Language][]cldr.Language
+	// DefaultLocale is tried last for every lookup.
+	DefaultLocale cldr.Language
+	// PseudoLocale, e.g. en_XA, is a virtual locale serving the messages of
+	// PseudoSource pseudo-localized: accented, lengthened and bracketed, as in
+	// "[Ĥéļļö ŵöŕļð !!!]". It lets QA spot hard-coded strings, truncation and
+	// concatenated messages without real translations.
+	PseudoLocale cldr.Language
+	// PseudoSource is the locale PseudoLocale is derived from. Defaults to
+	// DefaultLocale or, if that is unset, en_US.
+	PseudoSource cldr.Language
+	// OnFallback is called for every message served from a fallback locale.
+	OnFallback FallbackHook
+	// MissingKeyHandler is called for every lookup that finds no message.
+	// See MissingKeyCollector for a handler that reports missing keys.
+	MissingKeyHandler MissingKeyHandler
+	// SnapshotPath is a file where the catalog is saved after every successful
+	// sync. If it exists at startup, the client serves it right away and
+	// revalidates it against the source in the background.
+	SnapshotPath string
+	// Logger receives the client's logs, including those written by NewClient.
+	// Use NewSlogLogger for structured logging. Defaults to a DefaultLogger
+	// logging errors.
+	Logger Logger
+	// LookupLogLimit is the number of per-lookup messages, such as misses,
+	// logged per second. Zero means 10, a negative value means unlimited.
+	LookupLogLimit int
+	// Metrics receives measurements of lookups, syncs and dynamic fetches.
+	// Defaults to NopMetrics.
+	Metrics Metrics
+	// DynamicCacheTTL is how long values fetched by DynamicContent, and keys
+	// the source has no value for, are cached. Concurrent fetches of the same
+	// value share one request either way. Zero disables the cache.
+	DynamicCacheTTL time.Duration
+	// SyncDynamicContent loads every dynamic value of the key passed to
+	// EnableDynamicContent with LoadAllDynamic, and syncs them along with the
+	// static values, revalidated by checksum. DynamicContent serves the synced
+	// keys from memory and fetches only those the source added since.
+	SyncDynamicContent bool
+	// FlushBatchSize is the number of values saved per SaveDynamic call when
+	// the writes queued under SaveStrategyOnDemand are flushed. Zero means 100.
+	FlushBatchSize int
+	// FlushThreshold is the number of queued writes that starts a flush in the
+	// background. Zero disables it.
+	FlushThreshold int
+	// FlushInterval is how often queued writes are flushed in the background.
+	// Zero disables it, leaving them to Flush and Close.
+	FlushInterval time.Duration
 }
 
 type SaveStrategy int
@@ -29,7 +92,8 @@ const (
 )
 
 var (
-	ErrNoConfig = errors.New("no config provided")
+	ErrNoConfig      = errors.New("no config provided")
+	ErrUnknownLocale = errors.New("unknown locale")
 )
 
 func GetDefaultConfig(apiKey string) *Config {
@@ -38,22 +102,86 @@ func GetDefaultConfig(apiKey string) *Config {
 			"https://dev.wordapi.thesumm.it/api/v1",
 			apiKey,
 		),
-		UpdateInterval: 10 * time.Second,
-		MaxCacheSizeMB: 256,
+		UpdateInterval:  10 * time.Second,
+		MaxCacheSizeMB:  256,
+		DynamicCacheTTL: time.Minute,
 	}
 }
 
 type Client struct {
-	httpClient              *http.Client
-	source                  source.Source
-	dynamicContentAccessKey string
+	httpClient *http.Client
+	source     source.Source
+	// dynamicContentAccessKey holds the key given to EnableDynamicContent,
+	// which lookups, flushes and syncs read concurrently; see accessKey.
+	dynamicContentAccessKey atomic.Value
 	logger                  Logger
 	checksum                string
 	updateInterval          time.Duration
+	maxBackoff              time.Duration
 	logLevel                int
 	maxCacheSizeMB          int
-	cache                   fluent.Map[cldr.Language, *fluent.Bundle]
+	maxDynamicEntries       int
+	cache                   *bundleCache
 	saveStrategy            SaveStrategy
+	fallbacks               map[cldr.Language][]cldr.Language
+	defaultLocale           cldr.Language
+	onFallback              FallbackHook
+	missingKeyHandler       MissingKeyHandler
+	snapshotPath            string
+	status                  *syncStatus
+	metrics                 Metrics
+	lookupLogs              *logLimiter
+	dynamicCache            *dynamicCache
+	syncDynamic             bool
+	// noBatch is set once the source refused a batch fetch with
+	// source.ErrBatchUnsupported, so TBatch fetches keys one at a time.
+	noBatch        atomic.Bool
+	flushBatchSize int
+	flushThreshold int
+	flushInterval  time.Duration
+
+	// ctx is cancelled by Close to stop the sync goroutine, which closes done on exit.
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// dynamicSyncMu guards the key dynamic values are synced for and its
+	// checksum. It is not held while the values are fetched.
+	dynamicSyncMu   sync.Mutex
+	dynamicSyncKey  string
+	dynamicChecksum string
+	// dynamicSyncs tracks the first syncs started by EnableDynamicContent.
+	dynamicSyncs sync.WaitGroup
+
+	// writes holds the dynamic writes not yet saved under SaveStrategyOnDemand;
+	// flushMu serializes their flushes. flushNow asks the flush job, which
+	// closes flushDone on exit, for a flush.
+	writes    writeQueue
+	flushMu   sync.Mutex
+	flushNow  chan struct{}
+	flushDone chan struct{}
+
+	// current is the catalog served to readers; writeMu serializes its updates.
+	current atomic.Pointer[catalog]
+	writeMu sync.Mutex
+
+	// savedSeqs and savedQueue order the saved dynamic values from oldest to
+	// newest for MaxDynamicEntries; both are guarded by writeMu.
+	savedSeq   uint64
+	savedSeqs  map[savedKey]uint64
+	savedQueue []savedItem
+
+	listenersMu sync.Mutex
+	listeners   []func(UpdateEvent)
+
+	// namespace is the namespace the client serves, "" for the shared one.
+	// The clients of other namespaces are created by Namespace and fall back
+	// to the shared client.
+	namespace    string
+	shared       *Client
+	namespacesMu sync.Mutex
+	namespaces   namespaces
 }
 
 func NewClient(config *Config) (SDK, error) {
@@ -69,173 +197,305 @@ func NewClient(config *Config) (SDK, error) {
 		logger: &DefaultLogger{
 			LogLevelError,
 		},
-		updateInterval: config.UpdateInterval,
-		maxCacheSizeMB: config.MaxCacheSizeMB,
-		saveStrategy:   config.SaveStrategy,
+		updateInterval:    config.UpdateInterval,
+		maxBackoff:        config.MaxBackoff,
+		maxCacheSizeMB:    config.MaxCacheSizeMB,
+		maxDynamicEntries: config.MaxDynamicEntries,
+		cache: &bundleCache{
+			maxBytes:     int64(config.MaxCacheSizeMB) * 1024 * 1024,
+			pseudo:       config.PseudoLocale,
+			pseudoSource: pseudoSource(config),
+		},
+		saveStrategy:      config.SaveStrategy,
+		fallbacks:         config.Fallbacks,
+		defaultLocale:     config.DefaultLocale,
+		onFallback:        config.OnFallback,
+		missingKeyHandler: config.MissingKeyHandler,
+		snapshotPath:      config.SnapshotPath,
+		status:            newSyncStatus(),
+		metrics:           config.Metrics,
+		dynamicCache:      newDynamicCache(config.DynamicCacheTTL),
+		syncDynamic:       config.SyncDynamicContent,
+		flushBatchSize:    config.FlushBatchSize,
+		flushThreshold:    config.FlushThreshold,
+		flushInterval:     config.FlushInterval,
+		flushNow:          make(chan struct{}, 1),
+	}
+	if c.metrics == nil {
+		c.metrics = NopMetrics{}
+	}
+	if config.Logger != nil {
+		c.logger = config.Logger
 	}
+	c.lookupLogs = &logLimiter{limit: config.LookupLogLimit}
+	if c.lookupLogs.limit == 0 {
+		c.lookupLogs.limit = defaultLookupLogLimit
+	}
+	c.cache.metrics = c.metrics
 
 	if config.Source == nil {
 		return nil, errors.New("source cannot be nil")
 	}
 
-	data, checksum, err := config.Source.LoadAllStatic("")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load translations: %v", err)
-	}
-	c.cache = fluent.NewMap[cldr.Language, *fluent.Bundle]()
+	c.cache.current = c.catalog
 
-	err = c.UpdateBundle(data)
-	if err != nil {
-		return nil, err
+	fromSnapshot := c.loadSnapshot()
+	if !fromSnapshot {
+		data, checksum, err := source.LoadAllStatic(context.Background(), config.Source, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load translations: %v", err)
+		}
+		shared, namespaced := splitNamespaces(data)
+		cat, err := newCatalog(c.cache, nil, newCatalogValues(shared), nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		cat.revisions = newRevisions(data)
+		c.current.Store(cat)
+		c.cache.enforce()
+		c.setNamespaces(namespaced, false)
+		c.checksum = checksum
+		c.status.success(checksum)
+		c.saveSnapshot(checksum, cat.static)
 	}
 
-	c.checksum = checksum
-
-	c.runSyncTranslationsJob()
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.done = make(chan struct{})
+	c.runSyncTranslationsJob(fromSnapshot)
+	if c.saveStrategy == SaveStrategyOnDemand && (c.flushInterval > 0 || c.flushThreshold > 0) {
+		c.runFlushJob()
+	}
 	return &c, nil
 }
 
 func (c *Client) EnableDynamicContent(key string) *DynamicContent {
-	c.dynamicContentAccessKey = key
+	c.dynamicContentAccessKey.Store(key)
+	for _, ns := range c.namespaceClients() {
+		ns.dynamicContentAccessKey.Store(key)
+	}
+	c.enableDynamicSync(key)
 	return &DynamicContent{
 		Client: c,
 	}
 }
+
+// accessKey returns the key given to EnableDynamicContent, or "" before it is
+// called.
+func (c *Client) accessKey() string {
+	key, _ := c.dynamicContentAccessKey.Load().(string)
+	return key
+}
+
 func (c *Client) Dynamic() *DynamicContent {
 	return &DynamicContent{
 		Client: c,
 	}
 }
 
-func (c *Client) runSyncTranslationsJob() {
-	c.syncTranslations()
+// runSyncTranslationsJob syncs once and then every update interval in the
+// background. With revalidate set, the first sync runs in the background too,
+// so a client started from a snapshot doesn't wait for the source.
+func (c *Client) runSyncTranslationsJob(revalidate bool) {
+	var failures int
+	if !revalidate {
+		failures = c.sync(c.ctx)
+		if c.updateInterval <= 0 {
+			close(c.done)
+			return
+		}
+	}
 	go func() {
-		for {
+		defer close(c.done)
+		if revalidate {
+			failures = c.sync(c.ctx)
 			if c.updateInterval <= 0 {
 				return
 			}
-			time.Sleep(c.updateInterval)
-			c.syncTranslations()
+		}
+		delay := c.nextDelay(failures)
+		c.status.scheduled(time.Now().Add(delay))
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				c.status.scheduled(time.Time{})
+				return
+			case <-timer.C:
+			}
+			failures = c.sync(c.ctx)
+			delay = c.nextDelay(failures)
+			c.status.scheduled(time.Now().Add(delay))
+			timer.Reset(delay)
 		}
 	}()
 }
 
-func (c *Client) syncTranslations() {
-	data, checksum, err := c.source.LoadAllStatic(c.checksum)
-	if err != nil {
-		c.logger.Errorf("Failed to sync translations: %v", err)
-		return
-	}
-
-	if checksum == c.checksum {
-		c.logger.Infof("Translations are up to date")
-		return
-	}
+// Close stops the background sync and flushes, saves the dynamic writes still
+// pending under SaveStrategyOnDemand and returns. If ctx expires first, ctx.Err() is returned.
+// Close may be called more than once.
+func (c *Client) Close(ctx context.Context) error {
+	c.closeOnce.Do(c.cancel)
 
-	c.checksum = checksum
+	synced := make(chan struct{})
+	go func() {
+		c.dynamicSyncs.Wait()
+		close(synced)
+	}()
 
-	err = c.UpdateBundle(data)
-	if err != nil {
-		c.logger.Errorf("Failed to update bundle: %v", err)
-		return
+	for _, done := range []chan struct{}{c.done, c.flushDone, synced} {
+		if done == nil {
+			continue
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
-	sizeMB := float64(c.GetCacheSize()) / 1024 / 1024
-	c.logger.Infof("Translations synced, count: %d, size %f MB", len(data), sizeMB)
-	//c.logger.Infof("Translations synced, count: %d", len(data))
-
-	if sizeMB > float64(c.maxCacheSizeMB) {
-		c.logger.Errorf("Warning! Cache size exceeded! %f MB", sizeMB)
+	if err := c.drainNamespaces(ctx); err != nil {
+		return err
 	}
+	return c.flushWrites(ctx)
 }
 
-func (c *Client) UpdateBundle(data []source.Object) error {
-
-	var mapData = make(map[string]map[string]*strings.Builder)
-
-	for _, item := range data {
-
-		localeMap, exists := mapData[item.LocaleCode]
-		if !exists {
-			localeMap = make(map[string]*strings.Builder)
-			mapData[item.LocaleCode] = localeMap
-		}
-		if _, bundleExists := localeMap[item.Key]; !bundleExists {
-			localeMap[item.Key] = &strings.Builder{}
-		}
-
-		key := strings.TrimSpace(item.Key)
-		value := item.Value
+// sync syncs the static catalog and then the dynamic values. It returns the
+// number of consecutive failed syncs of the static catalog.
+func (c *Client) sync(ctx context.Context) int {
+	failures := c.syncTranslations(ctx)
+	c.syncDynamicContent(ctx)
+	return failures
+}
 
-		// Make sure key doesn't contain invalid characters
-		if strings.ContainsAny(key, "\n\r") {
-			continue // Skip invalid keys
-		}
+// syncTranslations loads the static catalog from the source and publishes it
+// if it changed. It returns the number of consecutive failed syncs.
+func (c *Client) syncTranslations(ctx context.Context) int {
+	start := time.Now()
+	outcome := SyncFailed
+	defer func() {
+		c.metrics.Sync(time.Since(start), outcome)
+	}()
 
-		localeMap[item.Key].WriteString(key)
-		localeMap[item.Key].WriteString(" = ")
-		if len(value) == 0 {
-			localeMap[item.Key].WriteString(` `)
+	data, checksum, err := source.LoadAllStatic(ctx, c.source, c.checksum)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0
 		}
+		failures := c.status.failure(err)
+		c.log(slog.LevelError, "Failed to sync translations",
+			slog.String("source", c.sourceName()),
+			slog.Int("consecutive_failures", failures),
+			slog.Duration("duration", time.Since(start)),
+			slog.Any("error", err))
+		return failures
+	}
 
-		localeMap[item.Key].WriteString(fmt.Sprintf("%s", value))
-		localeMap[item.Key].WriteString("\n")
+	if checksum == c.checksum {
+		outcome = SyncUnchanged
+		c.status.success(checksum)
+		c.log(slog.LevelInfo, "Translations are up to date",
+			slog.String("source", c.sourceName()),
+			slog.String("checksum", checksum),
+			slog.Duration("duration", time.Since(start)))
+		return 0
 	}
 
-	for lang, builder := range mapData {
-		if len(builder) == 0 {
-			continue
+	shared, namespaced := splitNamespaces(data)
+	var (
+		static  = newCatalogValues(shared)
+		changes map[cldr.Language]LocaleChanges
+	)
+	err = c.updateCatalog(func(cur *catalog) (*catalog, error) {
+		changes = static.diff(cur.static)
+
+		rebuild := make(map[cldr.Language]struct{}, len(changes))
+		for l := range changes {
+			rebuild[l] = struct{}{}
 		}
-		var (
-			bundle *fluent.Bundle
-			ok     bool
-		)
-
-		if bundle, ok = c.cache.Exist(cldr.Language(lang)); !ok {
-			bundle = fluent.NewBundle(cldr.Language(lang))
+		cat, err := newCatalog(c.cache, cur, static, cur.dynamic, cur.saved, rebuild)
+		if err != nil {
+			return nil, err
 		}
+		cat.revisions = newRevisions(data)
+		return cat, nil
+	})
+	if err != nil {
+		failures := c.status.failure(err)
+		c.log(slog.LevelError, "Failed to update bundle",
+			slog.String("checksum", checksum),
+			slog.Any("error", err))
+		return failures
+	}
 
-		for key, sb := range builder {
-
-			content := sb.String()
-
-			if bundle.HasMessage(key) {
-
-				resource, errs := fluent.NewResource(content)
-				if errs != nil {
-					return fmt.Errorf("failed to create resource for language %s: %v", lang, errs)
-				}
-
-				bundle.AddResourceOverriding(resource)
-				c.logger.Debugf("Updated key '%s' for language '%s'", key, lang)
-
-				c.cache.Set(cldr.Language(lang), bundle)
-
-				continue
-
-			}
-			resource, errs := fluent.NewResource(content)
-			if errs != nil {
-				return fmt.Errorf("failed to create resource for language %s: %v", lang, errs)
-			}
-
-			if err := bundle.AddResource(resource); err != nil {
-				return fmt.Errorf("failed to add resource for language %s: %v", lang, err)
-			}
-
-			c.cache.Set(cldr.Language(lang), bundle)
+	namespaceChanges := c.setNamespaces(namespaced, false)
 
-		}
+	outcome = SyncUpdated
+	oldChecksum := c.checksum
+	c.checksum = checksum
+	c.status.success(checksum)
+	c.saveSnapshot(checksum, static)
+
+	if len(changes) > 0 {
+		c.notifyUpdate(UpdateEvent{
+			OldChecksum: oldChecksum,
+			NewChecksum: checksum,
+			Locales:     changes,
+		})
+	}
+	names := make([]string, 0, len(namespaceChanges))
+	for name := range namespaceChanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.notifyUpdate(UpdateEvent{
+			Namespace:   name,
+			OldChecksum: oldChecksum,
+			NewChecksum: checksum,
+			Locales:     namespaceChanges[name],
+		})
 	}
 
-	return nil
+	stats := c.CacheStats()
+	c.log(slog.LevelInfo, "Translations synced",
+		slog.String("source", c.sourceName()),
+		slog.String("checksum", checksum),
+		slog.Int("count", len(data)),
+		slog.Int("size_bytes", stats.Bytes),
+		slog.Int("loaded_locales", stats.LoadedLocales),
+		slog.Int("locales", stats.Locales),
+		slog.Duration("duration", time.Since(start)))
+	return 0
 }
 
-func (c *Client) GetCacheSize() int {
+// UpdateBundle adds data to the static catalog, replacing the values of keys
+// already present, and publishes the result as a new catalog. Objects of other
+// namespaces are added to their namespace; called on a namespace, all of data
+// is added to it.
+func (c *Client) UpdateBundle(data []source.Object) error {
+	if c.shared != nil {
+		return c.mergeStatic(newCatalogValues(data), nil)
+	}
 
-	size := 0
-	for keys := range c.cache.GetKeys() {
-		size += int(reflect.TypeOf(keys).Size())
+	shared, namespaced := splitNamespaces(data)
+	if err := c.mergeNamespaces(namespaced); err != nil {
+		return err
 	}
-	return size
+	return c.mergeStatic(newCatalogValues(shared), newRevisions(data))
+}
+
+// mergeStatic adds values to the static catalog. On the client of the shared
+// namespace, revs are added to the revisions of every namespace.
+func (c *Client) mergeStatic(values catalogValues, revs revisions) error {
+	return c.updateCatalog(func(cur *catalog) (*catalog, error) {
+		cat, err := newCatalog(c.cache, cur, cur.static.merge(values), cur.dynamic, cur.saved, values.localeSet())
+		if err != nil {
+			return nil, err
+		}
+		if c.shared == nil {
+			cat.revisions = cur.revisions.merge(revs)
+		}
+		return cat, nil
+	})
 }
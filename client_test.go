@@ -0,0 +1,101 @@
+package word
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// tempFtlClient creates a client backed by FTL files written to a temporary
+// directory, so tests may save translations without touching the fixtures.
+func tempFtlClient(t *testing.T, config Config, files map[string]string) (*Client, map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	paths := make(map[string]string, len(files))
+	for locale, content := range files {
+		path := filepath.Join(dir, locale+".ftl")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+		paths[locale] = path
+	}
+
+	db := source.NewFtl()
+	if err := db.AddLocaleFiles(paths); err != nil {
+		t.Fatalf("AddLocaleFiles() error = %v", err)
+	}
+
+	config.Source = db
+	sdk, err := NewClient(&config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return sdk.(*Client), paths
+}
+
+func TestClient_CloseStopsSync(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{UpdateInterval: 10 * time.Millisecond}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("sync goroutine is still running after Close")
+	}
+
+	// A second Close is a no-op.
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+}
+
+func TestClient_CloseDrainsPendingWrites(t *testing.T) {
+	c, paths := tempFtlClient(t, Config{SaveStrategy: SaveStrategyOnDemand}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+
+	err := c.Dynamic().SaveTranslationContext(context.Background(), "en_US", "pending_key", "Pending value")
+	if err != nil {
+		t.Fatalf("SaveTranslationContext() error = %v", err)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	b, err := os.ReadFile(paths["en_US"])
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(b), "pending_key = Pending value") {
+		t.Fatalf("pending write was not saved, file content:\n%s", b)
+	}
+}
+
+func TestDynamicContent_TContextCancelled(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got := c.Dynamic().TContext(ctx, "en_US", "missing"); got != "missing" {
+		t.Fatalf("TContext() = %q, want key", got)
+	}
+}
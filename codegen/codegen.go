@@ -0,0 +1,532 @@
+// Package codegen generates typed Go accessors for the messages of an FTL catalog.
+//
+// Every message key becomes a constant and every message a function taking a
+// *word.Localizer and one parameter per variable the message uses, so renamed
+// keys and changed variables turn into compile errors instead of lookups that
+// silently return the raw key.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/summit-fi/wordsdk-go/fluent/parser"
+	"github.com/summit-fi/wordsdk-go/fluent/parser/ast"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the generated package. Defaults to "msgs".
+	Package string
+	// DocLocale is the locale whose values document the generated functions.
+	// Defaults to en_US if present, otherwise the first locale in sort order.
+	DocLocale string
+}
+
+// Message describes a message as seen across all locales of a catalog.
+type Message struct {
+	Key string
+	// Variables are the variables used by the value of the message in any
+	// locale, including those of the messages it references, sorted by name.
+	Variables []Variable
+	// Attributes are the message's attributes in any locale, sorted by name.
+	Attributes []Attribute
+	// Doc is the value of the message in Options.DocLocale, if any.
+	Doc string
+}
+
+// Attribute is an attribute of a message, with the variables its pattern uses
+// in any locale, sorted by name.
+type Attribute struct {
+	Name      string
+	Variables []Variable
+}
+
+// Variable is a variable referenced by a message.
+type Variable struct {
+	Name string
+	// Type is the Go type of the generated parameter: string, int, float64 or time.Time.
+	Type string
+}
+
+// Type ranks: a variable used in several ways gets the highest ranked type.
+const (
+	typeString = iota
+	typeInt
+	typeFloat
+	typeTime
+)
+
+var typeNames = [...]string{
+	typeString: "string",
+	typeInt:    "int",
+	typeFloat:  "float64",
+	typeTime:   "time.Time",
+}
+
+// pluralCategories are the CLDR plural categories used as variant keys of
+// selectors over numbers.
+var pluralCategories = map[string]struct{}{
+	"zero": {}, "one": {}, "two": {}, "few": {}, "many": {}, "other": {},
+}
+
+// dateFunctions are the functions of fluent that format a date; their
+// arguments are time.Time. Arguments of other functions are strings.
+var dateFunctions = map[string]struct{}{
+	"UT_DATETIME": {}, "MMMMEEEED": {}, "YMMMMEEEED": {}, "YMMMD": {}, "MMMD": {},
+	"JM": {}, "HHMM": {}, "MMMED": {}, "YMMMED": {}, "JMS": {}, "YMD": {}, "E": {},
+	"MMM": {}, "MD": {}, "YM": {}, "Y": {}, "EEEEE": {}, "LLL": {}, "YMMMM": {},
+	"MMMMD": {}, "YMMMMD": {}, "EEE_D": {}, "YMMM": {},
+}
+
+// Analyze parses the values of objects and returns their messages sorted by key.
+// Values that don't parse are reported with their locale and key.
+func Analyze(objects []source.Object, docLocale string) ([]Message, error) {
+	// Messages per locale, so references are resolved within the same locale.
+	locales := make(map[string]map[string]*ast.Message)
+	for _, obj := range objects {
+		if obj.LocaleCode == "" || obj.Key == "" {
+			continue
+		}
+		resource, errs := parser.New(entry(obj.Key, obj.Value)).Parse()
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%s: %s: %v", obj.LocaleCode, obj.Key, errs[0])
+		}
+		for _, node := range resource.Body {
+			if msg, ok := node.(*ast.Message); ok {
+				if locales[obj.LocaleCode] == nil {
+					locales[obj.LocaleCode] = make(map[string]*ast.Message)
+				}
+				locales[obj.LocaleCode][msg.ID.Name] = msg
+			}
+		}
+	}
+
+	if docLocale == "" {
+		docLocale = defaultDocLocale(locales)
+	}
+
+	type info struct {
+		vars  map[string]int
+		attrs map[string]map[string]int
+		doc   string
+	}
+	infos := make(map[string]*info)
+	docs := make(map[string]string)
+	for _, obj := range objects {
+		if obj.LocaleCode == docLocale {
+			docs[obj.Key] = obj.Value
+		}
+	}
+
+	for _, messages := range locales {
+		for key, msg := range messages {
+			in := infos[key]
+			if in == nil {
+				in = &info{vars: make(map[string]int), attrs: make(map[string]map[string]int), doc: docs[key]}
+				infos[key] = in
+			}
+			newCollector(messages, in.vars).reference(key, "")
+			for _, attr := range msg.Attributes {
+				name := attr.ID.Name
+				if in.attrs[name] == nil {
+					in.attrs[name] = make(map[string]int)
+				}
+				newCollector(messages, in.attrs[name]).reference(key, name)
+			}
+		}
+	}
+
+	result := make([]Message, 0, len(infos))
+	for key, in := range infos {
+		m := Message{Key: key, Doc: in.doc, Variables: variables(in.vars)}
+		for name, vars := range in.attrs {
+			m.Attributes = append(m.Attributes, Attribute{Name: name, Variables: variables(vars)})
+		}
+		sort.Slice(m.Attributes, func(i, j int) bool { return m.Attributes[i].Name < m.Attributes[j].Name })
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// variables returns the variables of vars, by name, sorted by name.
+func variables(vars map[string]int) []Variable {
+	var result []Variable
+	for name, rank := range vars {
+		result = append(result, Variable{Name: name, Type: typeNames[rank]})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func defaultDocLocale(locales map[string]map[string]*ast.Message) string {
+	if _, ok := locales["en_US"]; ok {
+		return "en_US"
+	}
+	var first string
+	for l := range locales {
+		if first == "" || l < first {
+			first = l
+		}
+	}
+	return first
+}
+
+// entry formats a value loaded by a source as an FTL entry, the way the client does.
+func entry(key, value string) string {
+	if value == "" {
+		value = " "
+	}
+	return key + " = " + value + "\n"
+}
+
+// collector gathers the variables of a pattern and of the patterns it
+// references into vars.
+type collector struct {
+	messages map[string]*ast.Message
+	vars     map[string]int
+	visited  map[string]struct{}
+}
+
+func newCollector(messages map[string]*ast.Message, vars map[string]int) *collector {
+	return &collector{messages: messages, vars: vars, visited: make(map[string]struct{})}
+}
+
+// reference records the variables of the value of the message id or, if attr
+// is set, of its attribute attr. Each pattern is visited once.
+func (c *collector) reference(id, attr string) {
+	ref := id
+	if attr != "" {
+		ref += "." + attr
+	}
+	if _, ok := c.visited[ref]; ok {
+		return
+	}
+	c.visited[ref] = struct{}{}
+
+	msg := c.messages[id]
+	if msg == nil {
+		return
+	}
+	if attr == "" {
+		c.pattern(msg.Value)
+		return
+	}
+	for _, a := range msg.Attributes {
+		if a.ID.Name == attr {
+			c.pattern(a.Value)
+		}
+	}
+}
+
+func (c *collector) pattern(p *ast.Pattern) {
+	if p == nil {
+		return
+	}
+	for _, element := range p.Elements {
+		if placeable, ok := element.(*ast.Placeable); ok {
+			c.expression(placeable.Expression, typeString)
+		}
+	}
+}
+
+// expression records the variables of node, typed as rank if used directly.
+func (c *collector) expression(node ast.Node, rank int) {
+	switch n := node.(type) {
+	case *ast.VariableReference:
+		if cur, ok := c.vars[n.ID.Name]; !ok || rank > cur {
+			c.vars[n.ID.Name] = rank
+		}
+	case *ast.Placeable:
+		c.expression(n.Expression, rank)
+	case *ast.MessageReference:
+		// Referenced messages are formatted with the same variables.
+		var attr string
+		if n.Attribute != nil {
+			attr = n.Attribute.Name
+		}
+		c.reference(n.ID.Name, attr)
+	case *ast.FunctionReference:
+		argRank := typeString
+		if n.ID.Name == "NUMBER" {
+			argRank = typeFloat
+		} else if _, ok := dateFunctions[n.ID.Name]; ok {
+			argRank = typeTime
+		}
+		if n.Arguments != nil {
+			for _, arg := range n.Arguments.Positional {
+				c.expression(arg, argRank)
+			}
+		}
+	case *ast.SelectExpression:
+		c.expression(n.Selector, selectorRank(n))
+		for _, variant := range n.Variants {
+			c.pattern(variant.Value)
+		}
+	}
+	// Terms get their variables from their call arguments, which are literals.
+}
+
+// selectorRank types the selector of s as a number if its variants are plural
+// categories or numbers, and as a string otherwise.
+func selectorRank(s *ast.SelectExpression) int {
+	for _, variant := range s.Variants {
+		switch key := variant.Key.(type) {
+		case *ast.NumberLiteral:
+			return typeInt
+		case *ast.Identifier:
+			if _, ok := pluralCategories[key.Name]; !ok {
+				return typeString
+			}
+		}
+	}
+	return typeInt
+}
+
+// Generate returns the formatted Go source of the accessors for objects.
+func Generate(objects []source.Object, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "msgs"
+	}
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+
+	messages, err := Analyze(objects, opts.DocLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	data := templateData{Package: opts.Package}
+	names := make(map[string]string)
+	claim := func(name, key string) error {
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("keys %q and %q both generate %s", other, key, name)
+		}
+		names[name] = key
+		return nil
+	}
+
+	for _, m := range messages {
+		name := exportedName(m.Key)
+		if name == "" {
+			return nil, fmt.Errorf("key %q does not produce a Go identifier", m.Key)
+		}
+		fn := function{
+			Name:   name,
+			Const:  "Key" + name,
+			Key:    m.Key,
+			Doc:    docLines(m.Doc),
+			Params: params(m.Variables),
+		}
+		if err := claim(fn.Name, m.Key); err != nil {
+			return nil, err
+		}
+		if err := claim(fn.Const, m.Key); err != nil {
+			return nil, err
+		}
+		for _, attr := range m.Attributes {
+			acc := accessor{Name: name + exportedName(attr.Name), Attr: attr.Name, Params: params(attr.Variables)}
+			if err := claim(acc.Name, m.Key+"."+attr.Name); err != nil {
+				return nil, err
+			}
+			fn.Attrs = append(fn.Attrs, acc)
+		}
+		if fn.usesTime() {
+			data.ImportTime = true
+		}
+		data.Functions = append(data.Functions, fn)
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+type templateData struct {
+	Package    string
+	ImportTime bool
+	Functions  []function
+}
+
+type function struct {
+	Name   string
+	Const  string
+	Key    string
+	Doc    []string
+	Params []param
+	Attrs  []accessor
+}
+
+// usesTime reports whether a parameter of fn or of its attributes is a time.Time.
+func (fn function) usesTime() bool {
+	for _, p := range fn.Params {
+		if p.Type == "time.Time" {
+			return true
+		}
+	}
+	for _, acc := range fn.Attrs {
+		for _, p := range acc.Params {
+			if p.Type == "time.Time" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type param struct {
+	Name     string // Go parameter name
+	Variable string // FTL variable name
+	Type     string
+}
+
+// accessor is the function formatting an attribute of a message.
+type accessor struct {
+	Name   string
+	Attr   string
+	Params []param
+}
+
+// params turns variables into Go parameters with unique, valid names.
+func params(vars []Variable) []param {
+	used := map[string]struct{}{"l": {}}
+	result := make([]param, 0, len(vars))
+	for _, v := range vars {
+		name := unexportedName(v.Name)
+		if name == "" || token.IsKeyword(name) || isPredeclared(name) {
+			name += "_"
+		}
+		for {
+			if _, ok := used[name]; !ok {
+				break
+			}
+			name += "_"
+		}
+		used[name] = struct{}{}
+		result = append(result, param{Name: name, Variable: v.Name, Type: v.Type})
+	}
+	return result
+}
+
+func isPredeclared(name string) bool {
+	switch name {
+	case "string", "int", "float64", "time", "word", "any", "map", "len", "nil", "true", "false":
+		return true
+	}
+	return false
+}
+
+// exportedName converts an FTL identifier such as cart-items or cart_items to CartItems.
+func exportedName(id string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range id {
+		if r == '-' || r == '_' || r == '.' {
+			upper = true
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if sb.Len() == 0 && unicode.IsDigit(r) {
+			sb.WriteString("M")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// unexportedName converts an FTL identifier such as user-name to userName.
+func unexportedName(id string) string {
+	name := []rune(exportedName(id))
+	if len(name) == 0 {
+		return ""
+	}
+	name[0] = unicode.ToLower(name[0])
+	return string(name)
+}
+
+// docLines quotes the documenting value as comment lines.
+func docLines(doc string) []string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return nil
+	}
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return lines
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by wordsdk generate. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .ImportTime}}
+	"time"
+{{end}}
+	word "github.com/summit-fi/wordsdk-go"
+)
+
+// Message keys.
+const (
+{{- range .Functions}}
+	{{.Const}} = {{printf "%q" .Key}}
+{{- end}}
+)
+{{range $fn := .Functions}}
+// {{.Name}} formats the message {{.Key}}.
+{{- if .Doc}}
+//
+{{- range .Doc}}
+//	{{.}}
+{{- end}}
+{{- end}}
+func {{.Name}}(l *word.Localizer{{range .Params}}, {{.Name}} {{.Type}}{{end}}) string {
+{{- if .Params}}
+	return l.TA({{.Const}}, map[string]any{
+{{- range .Params}}
+		{{printf "%q" .Variable}}: {{.Name}},
+{{- end}}
+	})
+{{- else}}
+	return l.T({{.Const}})
+{{- end}}
+}
+{{range .Attrs}}
+// {{.Name}} formats the attribute {{.Attr}} of the message {{$fn.Key}}.
+func {{.Name}}(l *word.Localizer{{range .Params}}, {{.Name}} {{.Type}}{{end}}) string {
+{{- if .Params}}
+	return l.Attr({{$fn.Const}}, {{printf "%q" .Attr}}, map[string]any{
+{{- range .Params}}
+		{{printf "%q" .Variable}}: {{.Name}},
+{{- end}}
+	})
+{{- else}}
+	return l.Attr({{$fn.Const}}, {{printf "%q" .Attr}}, nil)
+{{- end}}
+}
+{{end}}
+{{- end}}
+`))
@@ -0,0 +1,135 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+var testObjects = []source.Object{
+	{LocaleCode: "en_US", Key: "cart-items", Value: "{ $count ->\n    [one] One item\n   *[other] { $count } items\n} in { $name }'s cart"},
+	{LocaleCode: "uk_UA", Key: "cart-items", Value: "{ $count } товарів у кошику { $name }. { greeting }"},
+	{LocaleCode: "uk_UA", Key: "greeting", Value: "Привіт, { $user-name }"},
+	{LocaleCode: "en_US", Key: "checkout", Value: "Pay { $amount }\n    .title = Checkout for { $name }\n    .label = { login.placeholder }"},
+	{LocaleCode: "en_US", Key: "login", Value: "Log in\n    .placeholder = Email for { $type }"},
+	{LocaleCode: "en_US", Key: "paid", Value: "Paid { NUMBER($amount) } on { YMD($date) }"},
+	{LocaleCode: "en_US", Key: "shout", Value: "{ UPPER($word) }"},
+	{LocaleCode: "en_US", Key: "title", Value: "Title"},
+}
+
+func TestAnalyze(t *testing.T) {
+	messages, err := Analyze(testObjects, "")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	byKey := make(map[string]Message, len(messages))
+	for _, m := range messages {
+		byKey[m.Key] = m
+	}
+
+	tests := []struct {
+		key   string
+		vars  []Variable
+		attrs []Attribute
+	}{
+		// Variables are merged across locales and referenced messages.
+		{"cart-items", []Variable{{"count", "int"}, {"name", "string"}, {"user-name", "string"}}, nil},
+		// The value and each attribute have variables of their own.
+		{"checkout", []Variable{{"amount", "string"}}, []Attribute{
+			{"label", []Variable{{"type", "string"}}},
+			{"title", []Variable{{"name", "string"}}},
+		}},
+		{"login", nil, []Attribute{{"placeholder", []Variable{{"type", "string"}}}}},
+		{"paid", []Variable{{"amount", "float64"}, {"date", "time.Time"}}, nil},
+		// Only the date functions take a time.Time.
+		{"shout", []Variable{{"word", "string"}}, nil},
+		{"title", nil, nil},
+	}
+	for _, tt := range tests {
+		m, ok := byKey[tt.key]
+		if !ok {
+			t.Errorf("Analyze() has no message %s", tt.key)
+			continue
+		}
+		if !reflect.DeepEqual(m.Variables, tt.vars) {
+			t.Errorf("%s variables = %v, want %v", tt.key, m.Variables, tt.vars)
+		}
+		if !reflect.DeepEqual(m.Attributes, tt.attrs) {
+			t.Errorf("%s attributes = %v, want %v", tt.key, m.Attributes, tt.attrs)
+		}
+	}
+	if doc := byKey["title"].Doc; doc != "Title" {
+		t.Errorf("title doc = %q, want the en_US value", doc)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	out, err := Generate(testObjects, Options{Package: "msgs"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	code := string(out)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "msgs.go", out, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		"// Code generated by wordsdk generate. DO NOT EDIT.",
+		"package msgs",
+		`KeyCartItems = "cart-items"`,
+		"func CartItems(l *word.Localizer, count int, name string, userName string) string {",
+		`"user-name": userName,`,
+		"func Login(l *word.Localizer) string {",
+		"func LoginPlaceholder(l *word.Localizer, type_ string) string {",
+		`return l.Attr(KeyLogin, "placeholder", map[string]any{`,
+		"func CheckoutTitle(l *word.Localizer, name string) string {",
+		"func Paid(l *word.Localizer, amount float64, date time.Time) string {",
+		"\t\"time\"\n",
+		"return l.T(KeyTitle)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []source.Object
+		opts    Options
+		want    string
+	}{
+		{
+			name: "colliding keys",
+			objects: []source.Object{
+				{LocaleCode: "en_US", Key: "cart-items", Value: "A"},
+				{LocaleCode: "en_US", Key: "cart_items", Value: "B"},
+			},
+			want: "both generate CartItems",
+		},
+		{
+			name:    "invalid value",
+			objects: []source.Object{{LocaleCode: "en_US", Key: "broken", Value: "{ $x"}},
+			want:    "en_US: broken",
+		},
+		{
+			name: "invalid package",
+			opts: Options{Package: "my-msgs"},
+			want: "invalid package name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.objects, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
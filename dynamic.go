@@ -1,7 +1,10 @@
 package word
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/summit-fi/wordsdk-go/fluent"
 	"github.com/summit-fi/wordsdk-go/fluent/cldr"
@@ -10,78 +13,186 @@ import (
 
 type DynamicContent struct {
 	*Client
+	// prefetched holds the values TBatch fetched for its keys, which
+	// loadDynamic serves instead of fetching them one by one.
+	prefetched map[string]fetchResult
 }
 
 func (d *DynamicContent) T(lang string, key string) string {
+	return d.TContext(context.Background(), lang, key)
+}
 
-	bundle := d.cache.Get(cldr.Language(lang))
+// TContext is T with a context; ctx bounds the source request made when the key
+// is not in the local bundle.
+func (d *DynamicContent) TContext(ctx context.Context, lang string, key string) string {
+	return d.translate(ctx, lang, key, nil)
+}
 
-	if bundle == nil {
-		d.logger.Debugf("Bundle for language '%s' is nil, returning key: %s", lang, key)
-		return key
-	}
+func (d *DynamicContent) TA(lang, key string, args any) string {
+	return d.TAContext(context.Background(), lang, key, args)
+}
 
-	if bundle.HasMessage(key) {
-		message, errs, err := bundle.FormatMessage(key)
-		if err != nil {
-			d.logger.Debugf("Failed to format message for key '%s': %v, stack:%v", key, err, errs)
-			return key
-		}
-		d.logger.Debugf("Translated %s: %s", key, message)
-		return message
-	}
+func (d *DynamicContent) TAContext(ctx context.Context, lang, key string, args any) string {
+	return d.translate(ctx, lang, key, args, fluent.WithArgs(args))
+}
 
-	datum, err := d.source.LoadOneDynamic(d.dynamicContentAccessKey, lang, key)
-	if err != nil {
-		d.logger.Errorf("Failed to get dynamic content: %v", err)
-		return key
-	}
+// translate looks key up and formats it; see lookup.
+func (d *DynamicContent) translate(ctx context.Context, lang, key string, args any, contexts ...*fluent.FormatContext) string {
+	message, _ := d.translateError(ctx, lang, key, args, contexts...)
+	return message
+}
 
-	if len(datum) == 0 {
-		d.logger.Debugf("translation '%s' not found", key)
-		return key
-	}
+// TE is T returning the diagnostics of the lookup, see Client.TE.
+func (d *DynamicContent) TE(lang, key string) (string, error) {
+	return d.TEContext(context.Background(), lang, key)
+}
 
-	d.logger.Debugf("Translated %s: %s", key, datum)
-	return datum
+func (d *DynamicContent) TEContext(ctx context.Context, lang, key string) (string, error) {
+	return d.translateError(ctx, lang, key, nil)
 }
 
-func (d *DynamicContent) TA(lang, key string, args any) string {
-	bundle := d.cache.Get(cldr.Language(lang))
+// TAE is TA returning the diagnostics of the lookup, see Client.TAE.
+func (d *DynamicContent) TAE(lang, key string, args any) (string, error) {
+	return d.TAEContext(context.Background(), lang, key, args)
+}
 
-	if bundle == nil {
-		d.logger.Debugf("Bundle for language '%s' is nil, returning key: %s", lang, key)
-		return key
+func (d *DynamicContent) TAEContext(ctx context.Context, lang, key string, args any) (string, error) {
+	return d.translateError(ctx, lang, key, args, fluent.WithArgs(args))
+}
+
+// translateError is translate returning the diagnostics of the lookup.
+// Plain dynamic values are never formatted, so they have none.
+func (d *DynamicContent) translateError(ctx context.Context, lang, key string, args any, contexts ...*fluent.FormatContext) (string, error) {
+	bundle, datum := d.lookup(ctx, lang, key)
+	switch {
+	case bundle != nil:
+		message, err := d.formatMessageError(bundle, key, contexts...)
+		return d.reveal(ctx, key, cldr.Language(lang), bundle, message), err
+	case datum != "":
+		if mode := RevealFromContext(ctx); mode != RevealOff {
+			datum = revealMessage(mode, Revealed{Key: key, Locale: cldr.Language(lang), Origin: OriginDynamic, Text: datum})
+		}
+		return datum, nil
+	default:
+		message, err := d.missingError(lang, key, args)
+		return d.reveal(ctx, key, cldr.Language(lang), nil, message), err
 	}
+}
+
+// lookup looks key up in lang's bundle and synced dynamic values, then in the
+// dynamic source and finally in the bundles of lang's fallback chain. It
+// returns the bundle holding the message or the value fetched from the source;
+// both are empty if key is missing.
+func (d *DynamicContent) lookup(ctx context.Context, lang, key string) (*fluent.Bundle, string) {
+	d.metrics.Lookup(d.metricLocale(cldr.Language(lang)))
+
+	chain := d.fallbackChain(cldr.Language(lang))
+	cat := d.catalog()
 
-	if bundle.HasMessage(key) {
-		message, errs, err := bundle.FormatMessage(key, fluent.WithVariables(args.(map[string]any)))
+	if bundle, _ := findDynamicBundle(cat, chain[:1], key); bundle != nil {
+		return bundle, ""
+	}
+
+	// A namespace fetches values of the locales of the shared namespace too.
+	if d.servesLocale(cat, chain[0]) {
+		start := time.Now()
+		datum, err := d.loadDynamic(ctx, lang, key)
+		duration := time.Since(start)
 		if err != nil {
-			d.logger.Debugf("Failed to format message for key '%s': %v, stack:%v", key, err, errs)
-			return key
+			d.log(slog.LevelError, "Failed to get dynamic content",
+				slog.String("lang", lang),
+				slog.String("key", key),
+				slog.String("source", d.sourceName()),
+				slog.Duration("duration", duration),
+				slog.Any("error", err))
+		} else if len(datum) > 0 {
+			d.logLookup(slog.LevelDebug, "Translated dynamic content",
+				slog.String("lang", lang),
+				slog.String("key", key),
+				slog.Duration("duration", duration))
+			return nil, datum
+		} else {
+			d.logLookup(slog.LevelDebug, "No dynamic content", slog.String("lang", lang), slog.String("key", key))
 		}
-		d.logger.Debugf("Translated %s: %s", key, message)
-		return message
+	} else {
+		d.logLookup(slog.LevelDebug, "No bundle for language", slog.String("lang", lang), slog.String("key", key))
 	}
 
-	datum, err := d.source.LoadOneDynamic(d.dynamicContentAccessKey, lang, key)
+	bundle, served := findDynamicBundle(cat, chain[1:], key)
+	if bundle == nil && d.shared != nil {
+		bundle, served = findDynamicBundle(d.shared.catalog(), chain, key)
+	}
+	if bundle != nil && served != chain[0] {
+		d.reportFallback(chain[0], served, key)
+	}
+	return bundle, ""
+}
 
-	if err != nil {
-		d.logger.Errorf("Failed to get dynamic content: %v", err)
-		return key
+// servesLocale reports whether cat or, for a namespace, the shared catalog has
+// static or synced dynamic values of lang, so that values missing from them
+// are worth fetching from the source.
+func (d *DynamicContent) servesLocale(cat *catalog, lang cldr.Language) bool {
+	if cat.bundle(lang) != nil || cat.syncedBundle(lang) != nil {
+		return true
+	}
+	if d.shared == nil {
+		return false
 	}
+	shared := d.shared.catalog()
+	return shared.bundle(lang) != nil || shared.syncedBundle(lang) != nil
+}
 
-	if len(datum) == 0 {
-		d.logger.Debugf("translation '%s' not found", key)
-		return key
+// TM returns the value and attributes of the message key, fetching it from the
+// source if it is not in the local bundle, with the fallbacks of T.
+func (d *DynamicContent) TM(lang, key string, args any) *fluent.FormattedMessage {
+	return d.TMContext(context.Background(), lang, key, args)
+}
+
+func (d *DynamicContent) TMContext(ctx context.Context, lang, key string, args any) *fluent.FormattedMessage {
+	bundle, datum := d.lookup(ctx, lang, key)
+	switch {
+	case bundle != nil:
+		return d.formatFullMessage(bundle, key, fluent.WithArgs(args))
+	case datum != "":
+		return d.dynamicMessage(cldr.Language(lang), key, datum, args)
+	default:
+		return d.missingMessage(lang, key, args)
 	}
+}
+
+// TAttr returns the attribute attr of the message key; see TM.
+func (d *DynamicContent) TAttr(lang, key, attr string, args any) string {
+	return d.TAttrContext(context.Background(), lang, key, attr, args)
+}
+
+func (d *DynamicContent) TAttrContext(ctx context.Context, lang, key, attr string, args any) string {
+	return d.attr(lang, key, attr, args, d.TMContext(ctx, lang, key, args))
+}
 
-	return datum
+// dynamicMessage formats a value fetched from the source. Values that don't
+// parse as a message are returned as they are, like T does.
+func (d *DynamicContent) dynamicMessage(lang cldr.Language, key, datum string, args any) *fluent.FormattedMessage {
+	resource, errs := fluent.NewResource(source.FormatFTLEntry(key, datum))
+	if errs == nil {
+		bundle := fluent.NewBundle(lang)
+		if bundle.AddResource(resource) == nil && bundle.HasMessage(key) {
+			return d.formatFullMessage(bundle, key, fluent.WithArgs(args))
+		}
+	}
+	return &fluent.FormattedMessage{Value: &datum, Attributes: map[string]string{}}
 }
 
-func (d *DynamicContent) saveObjects(data []source.Object) error {
+func (d *DynamicContent) saveObjects(ctx context.Context, data []source.Object) error {
+	if d.namespace != "" {
+		scoped := make([]source.Object, len(data))
+		for i, item := range data {
+			item.Namespace = d.namespace
+			scoped[i] = item
+		}
+		data = scoped
+	}
 	if d.saveStrategy == SaveStrategyImmediate {
-		err := d.source.SaveDynamic(d.dynamicContentAccessKey, data)
+		err := source.SaveDynamic(ctx, d.source, d.accessKey(), data)
 		if err != nil {
 			return err
 		}
@@ -90,6 +201,7 @@ func (d *DynamicContent) saveObjects(data []source.Object) error {
 		d.updateSaveBundleWithData(data)
 	} else if d.saveStrategy == SaveStrategyOnDemand {
 		d.updateSaveBundleWithData(data)
+		d.enqueueWrites(data)
 	} else {
 		return fmt.Errorf("unknown save strategy: %v", d.saveStrategy)
 	}
@@ -97,88 +209,114 @@ func (d *DynamicContent) saveObjects(data []source.Object) error {
 }
 
 func (d *DynamicContent) SaveTranslation(lang string, key string, value string) error {
-	data := []source.Object{{LocaleCode: lang, Key: key, Value: value}}
-	if err := d.saveObjects([]source.Object{{LocaleCode: lang, Key: key, Value: value}}); err != nil {
-		return err
-	}
-	for _, datum := range data {
-		d.logger.Debugf("SaveTranslations(%s, %s, %s)", datum.LocaleCode, datum.Key, datum.Value)
-	}
-	return nil
+	return d.SaveTranslationContext(context.Background(), lang, key, value)
+}
+
+func (d *DynamicContent) SaveTranslationContext(ctx context.Context, lang string, key string, value string) error {
+	return d.saveObjects(ctx, []source.Object{{LocaleCode: lang, Key: key, Value: value}})
+}
+
+// SaveTranslationIf saves value only if the value of key in the source still
+// has revision, e.g. the Revision of the message returned by TM; otherwise
+// the save fails with a *source.ConflictError. Under SaveStrategyOnDemand the
+// conflict is reported by the flush. An empty revision saves unconditionally.
+func (d *DynamicContent) SaveTranslationIf(lang, key, value, revision string) error {
+	return d.SaveTranslationIfContext(context.Background(), lang, key, value, revision)
+}
+
+func (d *DynamicContent) SaveTranslationIfContext(ctx context.Context, lang, key, value, revision string) error {
+	return d.saveObjects(ctx, []source.Object{{LocaleCode: lang, Key: key, Value: value, Revision: revision}})
 }
 
 func (d *DynamicContent) SaveTranslations(data []source.Object) error {
-	err := d.saveObjects(data)
+	return d.SaveTranslationsContext(context.Background(), data)
+}
+
+func (d *DynamicContent) SaveTranslationsContext(ctx context.Context, data []source.Object) error {
+	err := d.saveObjects(ctx, data)
 	if err != nil {
-		d.logger.Errorf("Failed to save translations: %v", err)
+		d.log(slog.LevelError, "Failed to save translations",
+			slog.Int("count", len(data)),
+			slog.String("source", d.sourceName()),
+			slog.Any("error", err))
 		return err
 	}
-
-	for _, datum := range data {
-		d.logger.Debugf("SaveTranslations(%s, %s, %s)", datum.LocaleCode, datum.Key, datum.Value)
-	}
-
 	return nil
 }
 
 func (d *DynamicContent) updateSaveBundleWithData(data []source.Object) {
+	saved := make(catalogValues)
 	for _, item := range data {
-		bundle := d.cache.Get(cldr.Language(item.LocaleCode))
-		if bundle == nil {
-			bundle = fluent.NewBundle(cldr.Language(item.LocaleCode))
-			d.cache.Set(cldr.Language(item.LocaleCode), bundle)
+		if _, errs := fluent.NewResource(source.FormatFTLEntry(item.Key, item.Value)); errs != nil {
+			d.log(slog.LevelError, "Failed to parse saved value",
+				slog.String("lang", item.LocaleCode),
+				slog.String("key", item.Key),
+				slog.Any("error", errs))
+			continue
 		}
-		if !bundle.HasMessage(item.Key) {
-			d.logger.Debugf("Adding key '%s' for language '%s'", item.Key, item.LocaleCode)
-			resource, errs := fluent.NewResource(source.FormatFTLEntry(item.Key, item.Value))
-			if errs != nil {
-				d.logger.Errorf("Failed to create resource for language %s: %v", item.LocaleCode, errs)
-				continue
-			}
-			if err := bundle.AddResource(resource); err != nil {
-				d.logger.Errorf("Failed to add resource for language %s: %v", item.LocaleCode, err)
-				continue
-			}
-		} else {
-			resource, errs := fluent.NewResource(source.FormatFTLEntry(item.Key, item.Value))
-			if errs != nil {
-				d.logger.Errorf("Failed to create resource for language %s: %v", item.LocaleCode, errs)
-				continue
-			}
-			bundle.AddResourceOverriding(resource) // This method should override the existing resource if it exists
-			d.logger.Debugf("Updated key '%s' for language '%s'", item.Key, item.LocaleCode)
+
+		lang := cldr.Language(item.LocaleCode)
+		if saved[lang] == nil {
+			saved[lang] = make(map[string]string)
 		}
+		saved[lang][item.Key] = item.Value
+		d.log(slog.LevelDebug, "Saved value updated",
+			slog.String("lang", item.LocaleCode),
+			slog.String("key", item.Key))
 	}
 
-}
-
-// Flush saves all pending translations to the database.
-// This method is useful when SaveStrategy is set to SaveStrategyOnDemand.
-func (d *DynamicContent) Flush() error {
-	if d.saveStrategy != SaveStrategyOnDemand {
-		return fmt.Errorf("flush is only applicable when SaveStrategy is set to SaveStrategyOnDemand")
+	err := d.updateCatalog(func(cur *catalog) (*catalog, error) {
+		merged := cur.saved.merge(saved)
+		rebuild := saved.localeSet()
+		for _, l := range d.capSaved(merged, saved) {
+			rebuild[l] = struct{}{}
+		}
+		return newCatalog(d.cache, cur, cur.static, cur.dynamic, merged, rebuild)
+	})
+	if err != nil {
+		d.log(slog.LevelError, "Failed to update bundle", slog.Any("error", err))
 	}
+	// Values dropped by MaxDynamicEntries are fetched again, not served stale.
+	d.dynamicCache.forget(d.accessKey(), data)
+}
 
-	for locale, bundle := range d.cache.RetrieveAll() {
-		if bundle != nil {
-
-			var object []source.Object
-
-			for key, message := range bundle.RetrieveMessages() {
-				object = append(object, source.Object{
-					LocaleCode: string(locale),
-					Key:        key,
-					Value:      message,
-				})
+// dropSaved removes the values of data from the saved values, unless they were
+// saved again since, so the values of the source are served instead. It is
+// used for values the source refused.
+func (c *Client) dropSaved(data []source.Object) {
+	err := c.updateCatalog(func(cur *catalog) (*catalog, error) {
+		saved := cur.saved.merge(nil)
+		rebuild := make(map[cldr.Language]struct{})
+		for _, item := range data {
+			lang := cldr.Language(item.LocaleCode)
+			if value, ok := saved[lang][item.Key]; !ok || value != item.Value {
+				continue
 			}
-			err := d.SaveTranslations(object)
-			if err != nil {
-				return err
+			delete(saved[lang], item.Key)
+			if len(saved[lang]) == 0 {
+				delete(saved, lang)
 			}
+			delete(c.savedSeqs, savedKey{lang, item.Key})
+			rebuild[lang] = struct{}{}
 		}
-		d.logger.Debugf("Flushed translations for locale: %s", locale)
+		return newCatalog(c.cache, cur, cur.static, cur.dynamic, saved, rebuild)
+	})
+	if err != nil {
+		c.log(slog.LevelError, "Failed to update bundle", slog.Any("error", err))
 	}
+	c.dynamicCache.forget(c.accessKey(), data)
+}
 
-	return nil
+// Flush saves the values saved since the last flush, as they were saved, and
+// returns a *FlushError listing those that could not be; they are kept for the
+// next flush. This method is useful when SaveStrategy is set to SaveStrategyOnDemand.
+func (d *DynamicContent) Flush() error {
+	return d.FlushContext(context.Background())
+}
 
+func (d *DynamicContent) FlushContext(ctx context.Context) error {
+	if d.saveStrategy != SaveStrategyOnDemand {
+		return fmt.Errorf("flush is only applicable when SaveStrategy is set to SaveStrategyOnDemand")
+	}
+	return d.flushWrites(ctx)
 }
@@ -0,0 +1,146 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// maxConcurrentFetches bounds the single fetches TBatch makes when the source
+// can't fetch many keys at once.
+const maxConcurrentFetches = 8
+
+// fetchResult is the outcome of fetching one dynamic value; an empty value is
+// a miss.
+type fetchResult struct {
+	value string
+	err   error
+}
+
+// TBatch translates keys like T, fetching the values missing from lang's bundle
+// in one request when the source implements source.BatchSource and with
+// concurrent single requests otherwise. The result holds every key.
+func (d *DynamicContent) TBatch(lang string, keys []string) map[string]string {
+	return d.TBatchContext(context.Background(), lang, keys)
+}
+
+func (d *DynamicContent) TBatchContext(ctx context.Context, lang string, keys []string) map[string]string {
+	batch := &DynamicContent{Client: d.Client, prefetched: d.prefetch(ctx, lang, keys)}
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			result[key] = batch.translate(ctx, lang, key, nil)
+		}
+	}
+	return result
+}
+
+// prefetch fetches the dynamic values of the keys lookup would ask the source
+// for: those missing from lang's bundle, if lang is served at all.
+func (d *DynamicContent) prefetch(ctx context.Context, lang string, keys []string) map[string]fetchResult {
+	l := d.fallbackChain(cldr.Language(lang))[0]
+	cat := d.catalog()
+	if !d.servesLocale(cat, l) {
+		return nil
+	}
+
+	var missing []string
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if bundle, _ := findDynamicBundle(cat, []cldr.Language{l}, key); bundle != nil {
+			continue
+		}
+		seen[key] = struct{}{}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if batcher, ok := d.source.(source.BatchSource); ok && !d.noBatch.Load() {
+		return d.fetchBatch(ctx, batcher, lang, missing)
+	}
+	return d.fetchConcurrently(ctx, lang, missing)
+}
+
+// fetchBatch fetches the keys that aren't cached with one request. Keys the
+// source left out are cached as misses. If the request fails, the keys are
+// fetched one at a time instead; if the source has no batch requests at all,
+// later batches skip straight to that.
+func (d *DynamicContent) fetchBatch(ctx context.Context, batcher source.BatchSource, lang string, keys []string) map[string]fetchResult {
+	results := make(map[string]fetchResult, len(keys))
+	now := time.Now()
+	var uncached []string
+	for _, key := range keys {
+		k := dynamicCacheKey{accessKey: d.accessKey(), lang: lang, key: key}
+		if value, ok := d.dynamicCache.get(k, now); ok {
+			results[key] = fetchResult{value: value}
+			continue
+		}
+		uncached = append(uncached, key)
+	}
+	if len(uncached) == 0 {
+		return results
+	}
+
+	start := time.Now()
+	values, err := batcher.LoadManyDynamicContext(ctx, d.accessKey(), lang, uncached)
+	d.metrics.DynamicFetch(d.metricLocale(cldr.Language(lang)), time.Since(start), err)
+	if err != nil {
+		if errors.Is(err, source.ErrBatchUnsupported) && !d.noBatch.Swap(true) {
+			d.log(slog.LevelWarn, "Source has no batch requests, fetching keys one at a time",
+				slog.Any("error", err))
+		}
+		if ctx.Err() != nil {
+			for _, key := range uncached {
+				results[key] = fetchResult{err: err}
+			}
+			return results
+		}
+		for key, result := range d.fetchConcurrently(ctx, lang, uncached) {
+			results[key] = result
+		}
+		return results
+	}
+
+	now = time.Now()
+	for _, key := range uncached {
+		value := values[key]
+		d.dynamicCache.set(dynamicCacheKey{accessKey: d.accessKey(), lang: lang, key: key}, value, now)
+		results[key] = fetchResult{value: value}
+	}
+	return results
+}
+
+// fetchConcurrently fetches the keys with loadDynamic, maxConcurrentFetches at
+// a time, so they are cached and coalesced like single lookups.
+func (d *DynamicContent) fetchConcurrently(ctx context.Context, lang string, keys []string) map[string]fetchResult {
+	results := make(map[string]fetchResult, len(keys))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			value, err := d.loadDynamic(ctx, lang, key)
+			mu.Lock()
+			results[key] = fetchResult{value: value, err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,218 @@
+package word
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// batchStub is a countingStub that fetches many keys at once, recording the
+// keys of every batch.
+type batchStub struct {
+	countingStub
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (s *batchStub) LoadManyDynamic(accessKey, lang string, keys []string) (map[string]string, error) {
+	s.mu.Lock()
+	s.batches = append(s.batches, append([]string(nil), keys...))
+	s.mu.Unlock()
+
+	values := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := s.values[lang+":"+key]; ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *batchStub) LoadManyDynamicContext(ctx context.Context, accessKey, lang string, keys []string) (map[string]string, error) {
+	return s.LoadManyDynamic(accessKey, lang, keys)
+}
+
+func TestDynamicContent_TBatch(t *testing.T) {
+	src := &batchStub{countingStub: countingStub{values: map[string]string{
+		"en_US:chair": "Chair",
+		"en_US:table": "Table",
+	}}}
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local"}}
+	src.checksum = "v1"
+	sdk, err := NewClient(&Config{Source: src, DynamicCacheTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+	d := sdk.EnableDynamicContent("key")
+
+	keys := []string{"local", "chair", "table", "unknown", "chair"}
+	want := map[string]string{"local": "Local", "chair": "Chair", "table": "Table", "unknown": "unknown"}
+	if got := d.TBatch("en_US", keys); !reflect.DeepEqual(got, want) {
+		t.Errorf("TBatch() = %v, want %v", got, want)
+	}
+	if want := [][]string{{"chair", "table", "unknown"}}; !reflect.DeepEqual(src.batches, want) {
+		t.Errorf("batches = %q, want %q", src.batches, want)
+	}
+	if got := src.fetches.Load(); got != 0 {
+		t.Errorf("single fetches = %d, want 0", got)
+	}
+
+	// Values and misses are cached, so only the new key is fetched.
+	d.TBatch("en_US", []string{"chair", "unknown", "sofa"})
+	if got := src.batches[len(src.batches)-1]; !reflect.DeepEqual(got, []string{"sofa"}) {
+		t.Errorf("second batch = %q, want [sofa]", got)
+	}
+	if got := d.T("en_US", "table"); got != "Table" || src.fetches.Load() != 0 {
+		t.Errorf("T(table) = %q after %d fetches, want the cached value", got, src.fetches.Load())
+	}
+}
+
+func TestDynamicContent_TBatchWithoutBatchSource(t *testing.T) {
+	src := &countingStub{values: map[string]string{"en_US:chair": "Chair"}}
+	d := newCountingClient(t, 0, src)
+
+	got := d.TBatch("en_US", []string{"local", "chair", "unknown"})
+	want := map[string]string{"local": "Local", "chair": "Chair", "unknown": "unknown"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TBatch() = %v, want %v", got, want)
+	}
+	if got := src.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want one per key missing from the bundle", got)
+	}
+}
+
+func TestDynamicContent_TBatchWithoutBatchEndpoint(t *testing.T) {
+	var batches, singles atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/static/values":
+			w.Header().Set("ETag", "v1")
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"key": "local", "values": []map[string]string{{"locale": "en_US", "value": "Local"}}},
+			})
+		case "/dynamic/values/batch":
+			batches.Add(1)
+			http.NotFound(w, r)
+		case "/dynamic/value":
+			singles.Add(1)
+			if r.URL.Query().Get("key") != "chair" {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"value": "Chair"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sdk, err := NewClient(&Config{Source: source.NewRemote(srv.URL, "key"), DynamicCacheTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+	d := sdk.EnableDynamicContent("key")
+
+	want := map[string]string{"local": "Local", "chair": "Chair", "unknown": "unknown"}
+	if got := d.TBatch("en_US", []string{"local", "chair", "unknown"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("TBatch() = %v, want %v", got, want)
+	}
+	if got := singles.Load(); got != 2 {
+		t.Errorf("single fetches = %d, want 2", got)
+	}
+
+	// The endpoint is known to be missing, so it isn't asked again.
+	d.TBatch("en_US", []string{"table"})
+	if got := batches.Load(); got != 1 {
+		t.Errorf("batch requests = %d, want 1", got)
+	}
+	if got := singles.Load(); got != 3 {
+		t.Errorf("single fetches = %d, want 3", got)
+	}
+}
+
+func TestRemote_LoadManyDynamic(t *testing.T) {
+	var body struct {
+		Lang string   `json:"lang"`
+		Keys []string `json:"keys"`
+	}
+	var path, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"key": "chair", "values": []map[string]string{{"locale": "en_US", "value": "Chair"}, {"locale": "uk_UA", "value": "Стілець"}}},
+		})
+	}))
+	defer srv.Close()
+
+	remote := source.NewRemote(srv.URL, "key").WithNamespace("shop").(source.BatchSource)
+	values, err := remote.LoadManyDynamic("dynamic", "en_US", []string{"chair", "table"})
+	if err != nil {
+		t.Fatalf("LoadManyDynamic() error = %v", err)
+	}
+
+	if path != "/dynamic/values/batch" || query != "namespace=shop" {
+		t.Errorf("request = %s?%s, want /dynamic/values/batch?namespace=shop", path, query)
+	}
+	sort.Strings(body.Keys)
+	if body.Lang != "en_US" || !reflect.DeepEqual(body.Keys, []string{"chair", "table"}) {
+		t.Errorf("body = %+v, want en_US and both keys", body)
+	}
+	if want := map[string]string{"chair": "Chair"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("LoadManyDynamic() = %v, want %v", values, want)
+	}
+}
+
+func TestRemote_RetriesPerRequest(t *testing.T) {
+	// Every key fails twice before it is served.
+	var mu sync.Mutex
+	failures := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		mu.Lock()
+		failures[key]++
+		failed := failures[key] <= 2
+		mu.Unlock()
+		if failed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"value": "Value of " + key})
+	}))
+	defer srv.Close()
+	src := source.NewRemote(srv.URL, "token")
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := src.LoadOneDynamic("key", "en_US", key)
+			if err == nil && value != "Value of "+key {
+				t.Errorf("LoadOneDynamic(%s) = %q", key, value)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("LoadOneDynamic(%s) error = %v, want it retried", keys[i], err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+	"golang.org/x/sync/singleflight"
+)
+
+// dynamicCache holds the values DynamicContent fetched from the source, and
+// the keys the source didn't have, for Config.DynamicCacheTTL. Concurrent
+// fetches of the same value share one request to the source.
+type dynamicCache struct {
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[dynamicCacheKey]dynamicCacheEntry
+	// sweepAt is the number of entries at which expired ones are dropped.
+	sweepAt int
+}
+
+type dynamicCacheKey struct {
+	accessKey, lang, key string
+}
+
+// dynamicCacheEntry is a cached value; an empty value records a miss.
+type dynamicCacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+const minDynamicCacheSweep = 1024
+
+func newDynamicCache(ttl time.Duration) *dynamicCache {
+	return &dynamicCache{
+		ttl:     ttl,
+		entries: make(map[dynamicCacheKey]dynamicCacheEntry),
+		sweepAt: minDynamicCacheSweep,
+	}
+}
+
+// get returns the cached value of k and whether there is one. A cached miss
+// is reported as an empty value.
+func (dc *dynamicCache) get(k dynamicCacheKey, now time.Time) (string, bool) {
+	if dc.ttl <= 0 {
+		return "", false
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	entry, ok := dc.entries[k]
+	if !ok || now.After(entry.expires) {
+		return "", false
+	}
+	return entry.value, true
+}
+
+func (dc *dynamicCache) set(k dynamicCacheKey, value string, now time.Time) {
+	if dc.ttl <= 0 {
+		return
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if len(dc.entries) >= dc.sweepAt {
+		for key, entry := range dc.entries {
+			if now.After(entry.expires) {
+				delete(dc.entries, key)
+			}
+		}
+		dc.sweepAt = max(2*len(dc.entries), minDynamicCacheSweep)
+	}
+	dc.entries[k] = dynamicCacheEntry{value: value, expires: now.Add(dc.ttl)}
+}
+
+// forget drops the cached values of the objects, e.g. after they were saved.
+func (dc *dynamicCache) forget(accessKey string, data []source.Object) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	for _, item := range data {
+		delete(dc.entries, dynamicCacheKey{accessKey: accessKey, lang: item.LocaleCode, key: item.Key})
+	}
+}
+
+// clear drops every cached value.
+func (dc *dynamicCache) clear() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	clear(dc.entries)
+	dc.sweepAt = minDynamicCacheSweep
+}
+
+// loadDynamic returns the dynamic value of key in lang from the cache or,
+// failing that, from the source. Misses are returned as an empty value and a
+// nil error. Failed fetches are not cached.
+func (d *DynamicContent) loadDynamic(ctx context.Context, lang, key string) (string, error) {
+	if res, ok := d.prefetched[key]; ok {
+		return res.value, res.err
+	}
+	k := dynamicCacheKey{accessKey: d.accessKey(), lang: lang, key: key}
+	if value, ok := d.dynamicCache.get(k, time.Now()); ok {
+		return value, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	ch := d.dynamicCache.group.DoChan(k.accessKey+"\x00"+lang+"\x00"+key, func() (any, error) {
+		// The fetch is shared, so it must not fail because the caller that
+		// happened to start it went away.
+		fetchCtx := context.WithoutCancel(ctx)
+		start := time.Now()
+		value, err := source.LoadOneDynamic(fetchCtx, d.source, k.accessKey, lang, key)
+		if errors.Is(err, source.ErrNotFound) {
+			value, err = "", nil
+		}
+		d.metrics.DynamicFetch(d.metricLocale(cldr.Language(lang)), time.Since(start), err)
+		if err != nil {
+			return "", err
+		}
+		d.dynamicCache.set(k, value, time.Now())
+		return value, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+package word
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// countingStub serves values from a map, counting the fetches and holding
+// each one until release is closed.
+type countingStub struct {
+	stubSource
+	values  map[string]string
+	fetches atomic.Int32
+	release chan struct{}
+}
+
+func (s *countingStub) LoadOneDynamic(accessKey, lang, key string) (string, error) {
+	s.fetches.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	if value, ok := s.values[lang+":"+key]; ok {
+		return value, nil
+	}
+	return key, source.ErrNotFound
+}
+
+// joinContext calls joined the first time Done is called: loadDynamic only
+// waits on Done once the caller shares the fetch in flight.
+type joinContext struct {
+	context.Context
+	once   sync.Once
+	joined func()
+}
+
+func (c *joinContext) Done() <-chan struct{} {
+	c.once.Do(c.joined)
+	return c.Context.Done()
+}
+
+func newCountingClient(t *testing.T, ttl time.Duration, src *countingStub) *DynamicContent {
+	t.Helper()
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local"}}
+	src.checksum = "v1"
+	sdk, err := NewClient(&Config{Source: src, DynamicCacheTTL: ttl})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { sdk.Close(context.Background()) })
+	return sdk.EnableDynamicContent("key")
+}
+
+func TestDynamicContent_Cache(t *testing.T) {
+	src := &countingStub{values: map[string]string{"en_US:product": "Chair"}}
+	d := newCountingClient(t, time.Minute, src)
+
+	for i := 0; i < 3; i++ {
+		if got := d.T("en_US", "product"); got != "Chair" {
+			t.Fatalf("T(product) = %q, want %q", got, "Chair")
+		}
+		if got := d.T("en_US", "unknown"); got != "unknown" {
+			t.Fatalf("T(unknown) = %q, want the key", got)
+		}
+	}
+	if got := src.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2: one per key, misses included", got)
+	}
+
+	// Saving a key drops its cached value.
+	if err := d.SaveTranslation("en_US", "unknown", "Known"); err != nil {
+		t.Fatalf("SaveTranslation() error = %v", err)
+	}
+	if got := d.T("en_US", "unknown"); got != "Known" {
+		t.Errorf("T(unknown) after save = %q, want %q", got, "Known")
+	}
+}
+
+func TestDynamicContent_CacheDisabled(t *testing.T) {
+	src := &countingStub{values: map[string]string{"en_US:product": "Chair"}}
+	d := newCountingClient(t, 0, src)
+
+	d.T("en_US", "product")
+	d.T("en_US", "product")
+	if got := src.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2 without a cache", got)
+	}
+}
+
+func TestDynamicContent_CacheExpires(t *testing.T) {
+	src := &countingStub{values: map[string]string{"en_US:product": "Chair"}}
+	d := newCountingClient(t, time.Millisecond, src)
+
+	d.T("en_US", "product")
+	time.Sleep(5 * time.Millisecond)
+	d.T("en_US", "product")
+	if got := src.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2 after the entry expired", got)
+	}
+}
+
+func TestDynamicContent_CoalescesFetches(t *testing.T) {
+	src := &countingStub{
+		values:  map[string]string{"en_US:product": "Chair"},
+		release: make(chan struct{}),
+	}
+	d := newCountingClient(t, 0, src)
+
+	const callers = 10
+	var wg, joined sync.WaitGroup
+	results := make([]string, callers)
+	for i := range results {
+		wg.Add(1)
+		joined.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := &joinContext{Context: context.Background(), joined: joined.Done}
+			results[i] = d.TContext(ctx, "en_US", "product")
+		}()
+	}
+	// Hold the fetch until every caller waits for it.
+	joined.Wait()
+	close(src.release)
+	wg.Wait()
+
+	for i, got := range results {
+		if got != "Chair" {
+			t.Errorf("caller %d got %q, want %q", i, got, "Chair")
+		}
+	}
+	if got := src.fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+}
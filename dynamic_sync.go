@@ -0,0 +1,115 @@
+package word
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// enableDynamicSync makes key the one dynamic values are synced for and starts
+// loading them in the background, if Config.SyncDynamicContent is set. Until
+// they are loaded, lookups fetch values from the source as without the sync.
+// The values of the key synced before are replaced.
+func (c *Client) enableDynamicSync(key string) {
+	if !c.syncDynamic {
+		return
+	}
+	c.dynamicSyncMu.Lock()
+	changed := c.dynamicSyncKey != key
+	if changed {
+		c.dynamicSyncKey = key
+		c.dynamicChecksum = ""
+	}
+	c.dynamicSyncMu.Unlock()
+
+	if changed {
+		c.dynamicSyncs.Add(1)
+		go func() {
+			defer c.dynamicSyncs.Done()
+			c.syncDynamicContent(c.ctx)
+		}()
+	}
+}
+
+// syncDynamicContent loads the dynamic values of the enabled key and publishes
+// them if they changed. On failure the values last synced are kept. The values
+// are fetched without holding dynamicSyncMu, and dropped if the key changed in
+// the meantime.
+func (c *Client) syncDynamicContent(ctx context.Context) {
+	if !c.syncDynamic {
+		return
+	}
+	c.dynamicSyncMu.Lock()
+	key, checksumIn := c.dynamicSyncKey, c.dynamicChecksum
+	c.dynamicSyncMu.Unlock()
+	if key == "" {
+		return
+	}
+
+	start := time.Now()
+	data, checksum, err := source.LoadAllDynamic(ctx, c.source, key, checksumIn)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		c.log(slog.LevelError, "Failed to sync dynamic content",
+			slog.String("source", c.sourceName()),
+			slog.Duration("duration", time.Since(start)),
+			slog.Any("error", err))
+		return
+	}
+
+	if checksum != "" && checksum == checksumIn {
+		c.log(slog.LevelDebug, "Dynamic content is up to date",
+			slog.String("source", c.sourceName()),
+			slog.String("checksum", checksum),
+			slog.Duration("duration", time.Since(start)))
+		return
+	}
+
+	c.dynamicSyncMu.Lock()
+	defer c.dynamicSyncMu.Unlock()
+	if c.dynamicSyncKey != key {
+		// The values of the new key are loaded by the sync it started.
+		return
+	}
+
+	shared, namespaced := splitNamespaces(data)
+	if err := c.setDynamic(newCatalogValues(shared), newRevisions(data)); err != nil {
+		c.log(slog.LevelError, "Failed to update dynamic content",
+			slog.String("checksum", checksum),
+			slog.Any("error", err))
+		return
+	}
+	c.setNamespacesDynamic(namespaced)
+	c.dynamicChecksum = checksum
+
+	c.log(slog.LevelInfo, "Dynamic content synced",
+		slog.String("source", c.sourceName()),
+		slog.String("checksum", checksum),
+		slog.Int("count", len(data)),
+		slog.Duration("duration", time.Since(start)))
+}
+
+// setDynamic replaces the synced dynamic values and rebuilds the locales
+// whose values changed. On the client of the shared namespace, revs replaces
+// the revisions of the dynamic values of every namespace.
+func (c *Client) setDynamic(dynamic catalogValues, revs revisions) error {
+	return c.updateCatalog(func(cur *catalog) (*catalog, error) {
+		rebuild := make(map[cldr.Language]struct{})
+		for l := range dynamic.diff(cur.dynamic) {
+			rebuild[l] = struct{}{}
+		}
+		cat, err := newCatalog(c.cache, cur, cur.static, dynamic, cur.saved, rebuild)
+		if err != nil {
+			return nil, err
+		}
+		if c.shared == nil {
+			cat.dynamicRevisions = revs
+		}
+		return cat, nil
+	})
+}
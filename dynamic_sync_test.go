@@ -0,0 +1,263 @@
+package word
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// syncStub is a countingStub whose dynamic values are loaded in full from
+// dynamic, recording the checksum of every request.
+type syncStub struct {
+	countingStub
+	dynamic          []source.Object
+	dynamicChecksum  string
+	dynamicChecksums []string
+}
+
+func (s *syncStub) LoadAllDynamic(dynamicKey string, checksumIn string) ([]source.Object, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dynamicChecksums = append(s.dynamicChecksums, checksumIn)
+	if checksumIn == s.dynamicChecksum {
+		return nil, checksumIn, nil
+	}
+	return s.dynamic, s.dynamicChecksum, nil
+}
+
+func (s *syncStub) setDynamic(checksum string, objects ...source.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dynamic, s.dynamicChecksum = objects, checksum
+}
+
+func newSyncClient(t *testing.T, enabled bool, src *syncStub) *Client {
+	t.Helper()
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local"}}
+	src.checksum = "v1"
+	sdk, err := NewClient(&Config{Source: src, SyncDynamicContent: enabled})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { sdk.Close(context.Background()) })
+	return sdk.(*Client)
+}
+
+func TestDynamicContent_Sync(t *testing.T) {
+	src := &syncStub{}
+	src.setDynamic("d1", source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair"})
+	c := newSyncClient(t, true, src)
+	d := c.EnableDynamicContent("key")
+	c.dynamicSyncs.Wait()
+
+	if got := d.T("en_US", "product"); got != "Chair" {
+		t.Errorf("T(product) = %q, want %q", got, "Chair")
+	}
+	if got := src.fetches.Load(); got != 0 {
+		t.Errorf("fetches = %d, want synced keys served from memory", got)
+	}
+
+	// An unchanged sync keeps the values; a changed one replaces them.
+	c.sync(context.Background())
+	src.setDynamic("d2", source.Object{LocaleCode: "en_US", Key: "product", Value: "Sofa"})
+	c.sync(context.Background())
+	if got := d.T("en_US", "product"); got != "Sofa" {
+		t.Errorf("T(product) after sync = %q, want %q", got, "Sofa")
+	}
+	if want := []string{"", "d1", "d1"}; !reflect.DeepEqual(src.dynamicChecksums, want) {
+		t.Errorf("checksums = %q, want %q", src.dynamicChecksums, want)
+	}
+
+	// Saved values take precedence over synced ones.
+	if err := d.SaveTranslation("en_US", "product", "Table"); err != nil {
+		t.Fatalf("SaveTranslation() error = %v", err)
+	}
+	if got := d.T("en_US", "product"); got != "Table" {
+		t.Errorf("T(product) after save = %q, want %q", got, "Table")
+	}
+}
+
+func TestDynamicContent_SyncNamespaces(t *testing.T) {
+	src := &syncStub{}
+	src.setDynamic("d1",
+		source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair"},
+		source.Object{Namespace: "shop", LocaleCode: "en_US", Key: "product", Value: "Shop chair"},
+	)
+	c := newSyncClient(t, true, src)
+	c.EnableDynamicContent("key")
+	c.dynamicSyncs.Wait()
+
+	if got := c.Namespace("shop").Dynamic().T("en_US", "product"); got != "Shop chair" {
+		t.Errorf("shop T(product) = %q, want %q", got, "Shop chair")
+	}
+	if got := c.Dynamic().T("en_US", "product"); got != "Chair" {
+		t.Errorf("shared T(product) = %q, want %q", got, "Chair")
+	}
+}
+
+func TestDynamicContent_SyncInBackground(t *testing.T) {
+	src := &blockingSyncStub{release: make(chan struct{})}
+	src.values = map[string]string{"en_US:product": "Fetched chair"}
+	src.setDynamic("d1", source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair"})
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local"}}
+	src.checksum = "v1"
+	sdk, err := NewClient(&Config{Source: src, SyncDynamicContent: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+	c := sdk.(*Client)
+
+	// The first sync doesn't hold up EnableDynamicContent; until it is done,
+	// values are fetched as without the sync.
+	d := c.EnableDynamicContent("key")
+	if got := d.T("en_US", "product"); got != "Fetched chair" {
+		t.Errorf("T(product) before the sync = %q, want %q", got, "Fetched chair")
+	}
+
+	close(src.release)
+	c.dynamicSyncs.Wait()
+	d.dynamicCache.clear()
+	if got := d.T("en_US", "product"); got != "Chair" {
+		t.Errorf("T(product) after the sync = %q, want %q", got, "Chair")
+	}
+}
+
+// blockingSyncStub is a syncStub whose LoadAllDynamic waits for release.
+type blockingSyncStub struct {
+	syncStub
+	release chan struct{}
+}
+
+func (s *blockingSyncStub) LoadAllDynamic(dynamicKey string, checksumIn string) ([]source.Object, string, error) {
+	<-s.release
+	return s.syncStub.LoadAllDynamic(dynamicKey, checksumIn)
+}
+
+func TestDynamicContent_SyncKeepsStaticValues(t *testing.T) {
+	src := &syncStub{}
+	// Like Ftl and Postgres, the source serves its static values as dynamic ones.
+	src.setDynamic("d1",
+		source.Object{LocaleCode: "en_US", Key: "local", Value: "Synced local"},
+		source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair"},
+	)
+	c := newSyncClient(t, true, src)
+	before := c.CacheStats().Bytes
+	d := c.EnableDynamicContent("key")
+	c.dynamicSyncs.Wait()
+
+	// Static values are looked up first; synced ones are only added.
+	if got := d.T("en_US", "local"); got != "Local" {
+		t.Errorf("T(local) = %q, want %q", got, "Local")
+	}
+	if got := c.T("en_US", "product"); got != "product" {
+		t.Errorf("Client.T(product) = %q, want the key", got)
+	}
+	if got := d.T("en_US", "product"); got != "Chair" {
+		t.Errorf("T(product) = %q, want %q", got, "Chair")
+	}
+	if bundle := c.catalog().syncedBundle("en_US"); bundle == nil || bundle.HasMessage("local") {
+		t.Error("synced bundle holds a key of the static bundle")
+	}
+	if after := c.CacheStats().Bytes; after <= before {
+		t.Errorf("CacheStats().Bytes = %d after the sync, want more than %d", after, before)
+	}
+}
+
+func TestDynamicContent_SyncDisabled(t *testing.T) {
+	src := &syncStub{}
+	src.setDynamic("d1", source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair"})
+	c := newSyncClient(t, false, src)
+	c.EnableDynamicContent("key")
+	c.sync(context.Background())
+
+	if len(src.dynamicChecksums) != 0 {
+		t.Errorf("LoadAllDynamic called %d times, want 0", len(src.dynamicChecksums))
+	}
+}
+
+// keyedSyncStub serves the dynamic values of each key, holding the sync of
+// the key blocked until release is closed.
+type keyedSyncStub struct {
+	countingStub
+	dynamic map[string][]source.Object
+	blocked string
+	started chan struct{}
+}
+
+func (s *keyedSyncStub) LoadAllDynamic(dynamicKey string, checksumIn string) ([]source.Object, string, error) {
+	if dynamicKey == s.blocked {
+		close(s.started)
+		<-s.release
+	}
+	return s.dynamic[dynamicKey], dynamicKey, nil
+}
+
+func TestDynamicContent_SyncSwitchesKeys(t *testing.T) {
+	src := &keyedSyncStub{
+		dynamic: map[string][]source.Object{
+			"old": {{LocaleCode: "en_US", Key: "product", Value: "Old chair"}},
+			"new": {{LocaleCode: "en_US", Key: "product", Value: "New chair"}},
+		},
+		blocked: "old",
+		started: make(chan struct{}),
+	}
+	src.release = make(chan struct{})
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local"}}
+	src.checksum = "v1"
+	sdk, err := NewClient(&Config{Source: src, SyncDynamicContent: true})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+	c := sdk.(*Client)
+
+	c.EnableDynamicContent("old")
+	<-src.started
+
+	// Switching keys doesn't wait for the sync of the old one.
+	d := c.EnableDynamicContent("new")
+	close(src.release)
+	c.dynamicSyncs.Wait()
+
+	// The values of the old key arrived last or first, but are dropped either way.
+	if got := d.T("en_US", "product"); got != "New chair" {
+		t.Errorf("T(product) = %q, want %q", got, "New chair")
+	}
+}
+
+func TestDynamicContent_EnableDuringLookups(t *testing.T) {
+	src := &countingStub{values: map[string]string{"en_US:product": "Chair"}}
+	d := newCountingClient(t, 0, src)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			d.T("en_US", "product")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		d.EnableDynamicContent("key")
+	}
+	wg.Wait()
+}
+
+func TestDynamicContent_SyncedRevision(t *testing.T) {
+	src := &syncStub{}
+	src.setDynamic("d1", source.Object{LocaleCode: "en_US", Key: "product", Value: "Chair", Revision: "r1"})
+	c := newSyncClient(t, true, src)
+	d := c.EnableDynamicContent("key")
+	c.dynamicSyncs.Wait()
+
+	if got := d.TM("en_US", "product", nil); got.Text("") != "Chair" || got.Revision != "r1" {
+		t.Errorf("TM(product) = %q at %q, want %q at r1", got.Text(""), got.Revision, "Chair")
+	}
+	if got := d.TM("en_US", "local", nil); got.Revision != "" {
+		t.Errorf("TM(local).Revision = %q, want none for a value loaded without one", got.Revision)
+	}
+}
@@ -0,0 +1,100 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestClient_TAE(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": strings.Join([]string{
+			"hello = Hello, { $name }!",
+			"brand = { brand-name }",
+			"date = { TIMESTAMP($when) }",
+			"loop = { loop-back }",
+			"loop-back = { loop }",
+			"plain = Plain",
+		}, "\n") + "\n",
+	})
+	defer c.Close(context.Background())
+
+	tests := []struct {
+		name, key string
+		args      any
+		want      string
+		kind      error
+		ref       string
+	}{
+		{"ok", "hello", map[string]any{"name": "Ann"}, "Hello, Ann!", nil, ""},
+		{"no placeables", "plain", nil, "Plain", nil, ""},
+		{"unknown variable", "hello", nil, "Hello, {$name}!", fluent.ErrUnknownVariable, "$name"},
+		{"missing referenced message", "brand", nil, "{brand-name}", fluent.ErrMissingMessage, "brand-name"},
+		{"unknown function", "date", map[string]any{"when": 1}, "{TIMESTAMP}", fluent.ErrUnknownFunction, "TIMESTAMP"},
+		{"cyclic reference", "loop", nil, "{loop}", fluent.ErrCyclicReference, "loop"},
+		{"missing message", "unknown", nil, "unknown", fluent.ErrMissingMessage, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.TAE("en_US", tt.key, tt.args)
+			if got != tt.want {
+				t.Errorf("TAE() = %q, want %q", got, tt.want)
+			}
+			if tt.kind == nil {
+				if err != nil {
+					t.Errorf("TAE() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("TAE() error = %v, want %v", err, tt.kind)
+			}
+			var re *fluent.ResolveError
+			if !errors.As(err, &re) {
+				t.Fatalf("TAE() error = %T, want a *fluent.ResolveError", err)
+			}
+			if re.Key != tt.key || re.Name != tt.ref || re.Locale != cldr.LanguageEnUS {
+				t.Errorf("ResolveError = %+v, want key %q, name %q, locale en_US", re, tt.key, tt.ref)
+			}
+			if tt.ref != "" && re.Span == [2]uint{} {
+				t.Errorf("ResolveError.Span is zero, want the span of %s", tt.ref)
+			}
+		})
+	}
+
+	if got, want := c.TA("en_US", "hello", nil), "Hello, {$name}!"; got != want {
+		t.Errorf("TA() = %q, want the partial render %q", got, want)
+	}
+}
+
+func TestBundle_FunctionFailed(t *testing.T) {
+	resource, parseErrs := fluent.NewResource("boom = { BOOM() }\nnone = { NONE() }\n")
+	if len(parseErrs) > 0 {
+		t.Fatal(parseErrs)
+	}
+	bundle := fluent.NewBundle(cldr.LanguageEnUS)
+	bundle.AddResource(resource)
+	bundle.RegisterFunction("BOOM", func([]fluent.Value, map[string]fluent.Value, cldr.Language, ...string) fluent.Value {
+		panic("kaboom")
+	})
+	bundle.RegisterFunction("NONE", func([]fluent.Value, map[string]fluent.Value, cldr.Language, ...string) fluent.Value {
+		return nil
+	})
+
+	for _, key := range []string{"boom", "none"} {
+		got, errs, err := bundle.FormatMessage(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], fluent.ErrFunctionFailed) {
+			t.Errorf("FormatMessage(%q) errors = %v, want ErrFunctionFailed", key, errs)
+		}
+		if got != "{"+strings.ToUpper(key)+"}" {
+			t.Errorf("FormatMessage(%q) = %q", key, got)
+		}
+	}
+}
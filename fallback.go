@@ -0,0 +1,88 @@
+package word
+
+import (
+	"log/slog"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// FallbackHook is called whenever a message requested in one locale is served
+// from another locale of its fallback chain.
+type FallbackHook func(requested, served cldr.Language, key string)
+
+// fallbackChain returns the locales tried for lang, in order: lang itself, its
+// configured fallbacks and finally the default locale, without duplicates.
+func (c *Client) fallbackChain(lang cldr.Language) []cldr.Language {
+	chain := []cldr.Language{lang}
+	seen := map[cldr.Language]struct{}{lang: {}}
+
+	add := func(l cldr.Language) {
+		if l == "" {
+			return
+		}
+		if _, ok := seen[l]; ok {
+			return
+		}
+		seen[l] = struct{}{}
+		chain = append(chain, l)
+	}
+
+	for _, l := range c.fallbacks[lang] {
+		add(l)
+	}
+	add(c.defaultLocale)
+
+	return chain
+}
+
+// findBundle returns the first bundle of the given locales that has a message
+// for key, together with its locale. It returns a nil bundle if none has.
+func findBundle(cat *catalog, locales []cldr.Language, key string) (*fluent.Bundle, cldr.Language) {
+	for _, l := range locales {
+		bundle := cat.bundle(l)
+		if bundle != nil && bundle.HasMessage(key) {
+			return bundle, l
+		}
+	}
+	return nil, ""
+}
+
+// findDynamicBundle is findBundle for DynamicContent: the synced dynamic values
+// of each locale are looked up after its bundle.
+func findDynamicBundle(cat *catalog, locales []cldr.Language, key string) (*fluent.Bundle, cldr.Language) {
+	for _, l := range locales {
+		for _, bundle := range []*fluent.Bundle{cat.bundle(l), cat.syncedBundle(l)} {
+			if bundle != nil && bundle.HasMessage(key) {
+				return bundle, l
+			}
+		}
+	}
+	return nil, ""
+}
+
+// resolveBundle finds the bundle serving key for lang through its fallback chain
+// and reports a fallback hit if it is not lang's own bundle. A namespace that
+// has no message for key falls back to the shared namespace.
+func (c *Client) resolveBundle(lang cldr.Language, key string) *fluent.Bundle {
+	chain := c.fallbackChain(lang)
+	bundle, served := findBundle(c.catalog(), chain, key)
+	if bundle == nil && c.shared != nil {
+		bundle, served = findBundle(c.shared.catalog(), chain, key)
+	}
+	if bundle != nil && served != lang {
+		c.reportFallback(lang, served, key)
+	}
+	return bundle
+}
+
+func (c *Client) reportFallback(requested, served cldr.Language, key string) {
+	c.logLookup(slog.LevelDebug, "Message served from fallback locale",
+		slog.String("lang", string(requested)),
+		slog.String("key", key),
+		slog.String("served", string(served)))
+	c.metrics.Fallback(c.metricLocale(requested), served)
+	if c.onFallback != nil {
+		c.onFallback(requested, served, key)
+	}
+}
@@ -0,0 +1,64 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestClient_FallbackChain(t *testing.T) {
+	type hit struct {
+		requested, served cldr.Language
+		key               string
+	}
+	var hits []hit
+
+	c, _ := tempFtlClient(t, Config{
+		Fallbacks: map[cldr.Language][]cldr.Language{
+			cldr.LanguageUkUa: {cldr.LanguageRuUa},
+		},
+		DefaultLocale: cldr.LanguageEnUS,
+		OnFallback: func(requested, served cldr.Language, key string) {
+			hits = append(hits, hit{requested, served, key})
+		},
+	}, map[string]string{
+		"uk_UA": "own = Власний\n",
+		"ru_UA": "apples = { $count ->\n    [one] { $count } яблоко\n    [few] { $count } яблока\n   *[many] { $count } яблок\n}\n",
+		"en_US": "only_default = Default\n",
+	})
+	defer c.Close(context.Background())
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"own locale", c.T("uk_UA", "own"), "Власний"},
+		{"fallback locale with its plural rules", c.TA("uk_UA", "apples", map[string]any{"count": 3}), "3 яблока"},
+		{"default locale", c.T("uk_UA", "only_default"), "Default"},
+		{"missing everywhere", c.T("uk_UA", "missing"), "missing"},
+		{"unknown locale uses default", c.T("pl_PL", "only_default"), "Default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+
+	want := []hit{
+		{cldr.LanguageUkUa, cldr.LanguageRuUa, "apples"},
+		{cldr.LanguageUkUa, cldr.LanguageEnUS, "only_default"},
+		{"pl_PL", cldr.LanguageEnUS, "only_default"},
+	}
+	if len(hits) != len(want) {
+		t.Fatalf("hits = %v, want %v", hits, want)
+	}
+	for i := range want {
+		if hits[i] != want[i] {
+			t.Errorf("hits[%d] = %v, want %v", i, hits[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package fluent
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithArgs creates a FormatContext from the arguments of a lookup, which may be:
+//   - nil, for no variables;
+//   - a map with string keys, such as map[string]any;
+//   - a struct or a pointer to one, whose exported fields become variables named
+//     after their `word:"name"` tag or, without one, after the field. Fields
+//     tagged `word:"-"` are skipped and embedded structs are flattened.
+//
+// Anything else, and variables whose type can't be converted to a Value, are
+// reported as errors by FormatMessage instead of being dropped silently.
+func WithArgs(args interface{}) *FormatContext {
+	switch args := args.(type) {
+	case nil:
+		return WithVariables(nil)
+	case *FormatContext:
+		return args
+	case map[string]interface{}:
+		return WithVariables(args)
+	}
+
+	ctx := &FormatContext{
+		variables: make(map[string]Value),
+	}
+
+	rv := reflect.ValueOf(args)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ctx
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.Struct:
+		ctx.addFields(rv)
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		iter := rv.MapRange()
+		for iter.Next() {
+			ctx.addVariable(iter.Key().String(), iter.Value().Interface())
+		}
+	default:
+		ctx.errors = append(ctx.errors, fmt.Errorf("arguments: %w %T, want a map or a struct", ErrUnsupportedValue, args))
+	}
+	return ctx
+}
+
+// addFields adds the exported fields of the struct rv as variables.
+func (ctx *FormatContext) addFields(rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, hasTag := field.Tag.Lookup("word")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !hasTag {
+			embedded := rv.Field(i)
+			for embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					break
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				ctx.addFields(embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		value := rv.Field(i)
+		if !value.CanInterface() {
+			// A field promoted from an unexported embedded struct.
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		ctx.addVariable(name, value.Interface())
+	}
+}
@@ -2,6 +2,7 @@ package fluent
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ type Bundle struct {
 	messages  *Map[string, *ast.Message]
 	terms     *Map[string, *ast.Term]
 	functions map[string]Function
+	transform TextTransform
 }
 
 // NewBundle creates a new empty bundle
@@ -79,7 +81,7 @@ func (bundle *Bundle) RetrieveMessages() map[string]string {
 	result := make(map[string]string, len(all))
 
 	// Build a resolver once (no external contexts)
-	variables, functions := assembleContexts()
+	variables, functions, _ := assembleContexts()
 	for name, fn := range bundle.functions {
 		functions[name] = fn
 	}
@@ -97,6 +99,7 @@ func (bundle *Bundle) RetrieveMessages() map[string]string {
 		if message == nil || message.Value == nil {
 			continue
 		}
+		res.key = key
 		formatted := res.resolvePattern(message.Value).String()
 		if strings.TrimSpace(formatted) == "" || formatted == " " {
 			formatted = key
@@ -114,6 +117,13 @@ func (bundle *Bundle) RegisterFunction(name string, function Function) {
 	bundle.functions[strings.ToUpper(name)] = function
 }
 
+// SetTextTransform sets a function rewriting the text of every pattern the
+// bundle formats, e.g. PseudoText. It is applied to text elements only, never
+// to placeables, so variables and the results of functions are kept as they are.
+func (bundle *Bundle) SetTextTransform(transform TextTransform) {
+	bundle.transform = transform
+}
+
 func (bundle *Bundle) PrimaryLocale() cldr.Language {
 	if len(bundle.locales) > 0 {
 		return bundle.locales[0]
@@ -125,95 +135,143 @@ func (bundle *Bundle) PrimaryLocale() cldr.Language {
 type FormatContext struct {
 	variables map[string]Value
 	functions map[string]Function
+	// errors are the diagnostics of variables that could not be converted to a Value.
+	errors []error
 }
 
 // WithVariable creates a FormatContext with a single variable
 func WithVariable(key string, value interface{}) *FormatContext {
-	resolved := resolveValue(value)
-	if resolved == nil {
-		return &FormatContext{
-			variables: nil,
-			functions: nil,
-		}
-	}
-	return &FormatContext{
-		variables: map[string]Value{strings.TrimSpace(key): resolved},
+	ctx := &FormatContext{
+		variables: make(map[string]Value, 1),
 		functions: nil,
 	}
+	ctx.addVariable(key, value)
+	return ctx
 }
 
 // WithVariables creates a FormatContext with multiple variables
 func WithVariables(variables map[string]interface{}) *FormatContext {
-	cleaned := make(map[string]Value, len(variables))
-	for name, variable := range variables {
-		resolved := resolveValue(variable)
-		if resolved == nil {
-			continue
-		}
-		cleaned[strings.TrimSpace(name)] = resolved
-	}
-	return &FormatContext{
-		variables: cleaned,
+	ctx := &FormatContext{
+		variables: make(map[string]Value, len(variables)),
 		functions: nil,
 	}
+	for name, variable := range variables {
+		ctx.addVariable(name, variable)
+	}
+	return ctx
 }
 
-func resolveValue(value interface{}) Value {
-	if strVal, ok := value.(string); ok {
-		return String(strVal)
-	}
-	if strVal, ok := value.(*StringValue); ok {
-		return strVal
-	}
-	if boolVal, ok := value.(bool); ok {
-		if boolVal {
-			return String("true")
-		}
-		return String("false")
-	}
-	if float32Val, ok := value.(float32); ok {
-		return NumberLiteral(float32Val)
-	}
-	if float64Val, ok := value.(float64); ok {
-		return NumberLiteral(float32(float64Val))
-	}
-	if uintVal, ok := value.(uint); ok {
-		return NumberLiteral(float32(uintVal))
-	}
-	if uint8Val, ok := value.(uint8); ok {
-		return NumberLiteral(float32(uint8Val))
-	}
-	if uint16Val, ok := value.(uint16); ok {
-		return NumberLiteral(float32(uint16Val))
-	}
-	if uint32val, ok := value.(uint32); ok {
-		return NumberLiteral(float32(uint32val))
-	}
-	if uint64val, ok := value.(uint64); ok {
-		return NumberLiteral(float32(uint64val))
+// addVariable adds value as name, or a diagnostic if it can't be converted to a Value.
+func (ctx *FormatContext) addVariable(name string, value interface{}) {
+	name = strings.TrimSpace(name)
+	resolved, err := resolveValue(value)
+	if err != nil {
+		ctx.errors = append(ctx.errors, fmt.Errorf("variable '%s': %w", name, err))
+		return
 	}
-	if intVal, ok := value.(int); ok {
-		return NumberLiteral(float32(intVal))
-	}
-	if int8Val, ok := value.(int8); ok {
-		return NumberLiteral(float32(int8Val))
+	if resolved == nil {
+		return
 	}
-	if int16Val, ok := value.(int16); ok {
-		return NumberLiteral(float32(int16Val))
+	ctx.variables[name] = resolved
+}
+
+// resolveValue converts a variable to a Value. Besides the basic types it
+// accepts Valuer, pointers, fmt.Stringer and named types, in that order. It
+// returns a nil Value for nil variables and ErrUnsupportedValue for anything else.
+func resolveValue(value interface{}) (Value, error) {
+	// A typed nil, e.g. a nil *Money, is skipped like nil before its methods
+	// can be called.
+	rv := reflect.ValueOf(value)
+	if value == nil || rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
 	}
-	if int32val, ok := value.(int32); ok {
-		return NumberLiteral(float32(int32val))
+	if resolved, ok := resolveKnownValue(value); ok {
+		return resolved, nil
 	}
-	if int64val, ok := value.(int64); ok {
-		return NumberLiteral(float32(int64val))
+
+	if rv.Kind() == reflect.Pointer {
+		elem := rv.Elem().Interface()
+		if rv.Elem().Kind() == reflect.Pointer {
+			return resolveValue(elem)
+		}
+		if resolved, ok := resolveKnownValue(elem); ok {
+			return resolved, nil
+		}
+		// The pointer may implement fmt.Stringer where its element doesn't.
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return String(stringer.String()), nil
+		}
+		return resolveValue(elem)
 	}
-	if numVal, ok := value.(*NumberValue); ok {
-		return numVal
+
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return String(stringer.String()), nil
 	}
-	if timeVal, ok := value.(time.Time); ok {
-		return &DateTimeValue{Value: timeVal}
+
+	// Named types, e.g. type Count int.
+	switch rv.Kind() {
+	case reflect.String:
+		return String(rv.String()), nil
+	case reflect.Bool:
+		return resolveValue(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NumberLiteral(float32(rv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NumberLiteral(float32(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return NumberLiteral(float32(rv.Float())), nil
 	}
-	return nil
+
+	return nil, fmt.Errorf("%w %T", ErrUnsupportedValue, value)
+}
+
+// resolveKnownValue converts Valuer, Value implementations of this package and
+// the basic types.
+func resolveKnownValue(value interface{}) (Value, bool) {
+	switch v := value.(type) {
+	case Valuer:
+		return v.FluentValue(), true
+	case string:
+		return String(v), true
+	case *StringValue:
+		return v, true
+	case bool:
+		if v {
+			return String("true"), true
+		}
+		return String("false"), true
+	case float32:
+		return NumberLiteral(v), true
+	case float64:
+		return NumberLiteral(float32(v)), true
+	case uint:
+		return NumberLiteral(float32(v)), true
+	case uint8:
+		return NumberLiteral(float32(v)), true
+	case uint16:
+		return NumberLiteral(float32(v)), true
+	case uint32:
+		return NumberLiteral(float32(v)), true
+	case uint64:
+		return NumberLiteral(float32(v)), true
+	case int:
+		return NumberLiteral(float32(v)), true
+	case int8:
+		return NumberLiteral(float32(v)), true
+	case int16:
+		return NumberLiteral(float32(v)), true
+	case int32:
+		return NumberLiteral(float32(v)), true
+	case int64:
+		return NumberLiteral(float32(v)), true
+	case *NumberValue:
+		return v, true
+	case time.Time:
+		return &DateTimeValue{Value: v}, true
+	case *DateTimeValue:
+		return v, true
+	}
+	return nil, false
 }
 
 // WithFunction creates a FormatContext with a single function
@@ -237,10 +295,15 @@ func WithFunctions(functions map[string]Function) *FormatContext {
 }
 
 // TODO: Builtin functions (NUMBER, DATETIME)
-func assembleContexts(options ...*FormatContext) (map[string]Value, map[string]Function) {
+func assembleContexts(options ...*FormatContext) (map[string]Value, map[string]Function, []error) {
 	variables := make(map[string]Value)
 	functions := make(map[string]Function)
+	var errs []error
 	for _, option := range options {
+		if option == nil {
+			continue
+		}
+		errs = append(errs, option.errors...)
 		if option.variables != nil {
 			for key, variable := range option.variables {
 				variables[key] = variable
@@ -279,7 +342,7 @@ func assembleContexts(options ...*FormatContext) (map[string]Value, map[string]F
 	functions["EEE_D"] = EEE_D
 	functions["YMMM"] = YMMM
 
-	return variables, functions
+	return variables, functions, errs
 }
 
 // FormatMessage formats the message with the given key.
@@ -290,11 +353,11 @@ func assembleContexts(options ...*FormatContext) (map[string]Value, map[string]F
 // It may be just incomplete.
 func (bundle *Bundle) FormatMessage(key string, contexts ...*FormatContext) (string, []error, error) {
 	if bundle.messages.Get(key) == nil {
-		return "", nil, fmt.Errorf("message '%s' does not exist", key)
+		return "", nil, bundle.missingMessage(key)
 	}
 
 	msg := bundle.messages.Get(key)
-	variables, functions := assembleContexts(contexts...)
+	variables, functions, errs := assembleContexts(contexts...)
 
 	// Add the bundle's functions to the resolver's functions
 	for name, value := range bundle.functions {
@@ -303,12 +366,13 @@ func (bundle *Bundle) FormatMessage(key string, contexts ...*FormatContext) (str
 
 	res := &resolver{
 		bundle:          bundle,
+		key:             key,
 		primaryLanguage: bundle.locales[0],
 		params:          nil,
 		variables:       variables,
 		functions:       functions,
-		errors:          []error{},
-		activeMessages:  make(map[string]struct{}),
+		errors:          append([]error{}, errs...),
+		activeMessages:  map[string]struct{}{key: {}},
 	}
 	result := res.resolvePattern(msg.Value).String()
 	if strings.TrimSpace(result) == "" || result == " " {
@@ -319,11 +383,11 @@ func (bundle *Bundle) FormatMessage(key string, contexts ...*FormatContext) (str
 
 func (bundle *Bundle) FormatFullMessage(key string, contexts ...*FormatContext) (*FormattedMessage, []error, error) {
 	if bundle.messages.Get(key) == nil {
-		return nil, nil, fmt.Errorf("message '%s' does not exist", key)
+		return nil, nil, bundle.missingMessage(key)
 	}
 
 	msg := bundle.messages.Get(key)
-	variables, functions := assembleContexts(contexts...)
+	variables, functions, errs := assembleContexts(contexts...)
 
 	// Add the bundle's functions to the resolver's functions
 	for name, value := range bundle.functions {
@@ -332,12 +396,13 @@ func (bundle *Bundle) FormatFullMessage(key string, contexts ...*FormatContext)
 
 	res := &resolver{
 		bundle:          bundle,
+		key:             key,
 		primaryLanguage: bundle.locales[0],
 		params:          nil,
 		variables:       variables,
 		functions:       functions,
-		errors:          []error{},
-		activeMessages:  make(map[string]struct{}),
+		errors:          append([]error{}, errs...),
+		activeMessages:  map[string]struct{}{key: {}},
 	}
 
 	out := &FormattedMessage{
@@ -350,12 +415,22 @@ func (bundle *Bundle) FormatFullMessage(key string, contexts ...*FormatContext)
 	}
 
 	for _, attr := range msg.Attributes {
+		res.activeMessages = map[string]struct{}{key + "." + attr.ID.Name: {}}
 		out.Attributes[attr.ID.Name] = res.resolvePattern(attr.Value).String()
 	}
 
 	return out, res.errors, nil
 }
 
+// missingMessage is the error of FormatMessage for a key the bundle doesn't contain.
+func (bundle *Bundle) missingMessage(key string) error {
+	return &ResolveError{
+		Kind:   ErrMissingMessage,
+		Key:    key,
+		Locale: bundle.locales[0],
+	}
+}
+
 // Checks whether the bundle contains a message with the given key.
 func (bundle *Bundle) HasMessage(key string) bool {
 	return bundle.messages.Get(key) != nil
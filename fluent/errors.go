@@ -0,0 +1,70 @@
+package fluent
+
+import (
+	"fmt"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// The kinds of ResolveError, to be matched with errors.Is.
+var (
+	// ErrMissingMessage is reported for a message, term or attribute that doesn't exist.
+	ErrMissingMessage = resolveKind("missing message")
+	// ErrUnknownVariable is reported for a variable that wasn't passed to FormatMessage.
+	ErrUnknownVariable = resolveKind("unknown variable")
+	// ErrUnknownFunction is reported for a function that isn't registered in the bundle.
+	ErrUnknownFunction = resolveKind("unknown function")
+	// ErrCyclicReference is reported for a message or term that references itself.
+	ErrCyclicReference = resolveKind("cyclic reference")
+	// ErrFunctionFailed is reported for a function that panicked or returned no value.
+	ErrFunctionFailed = resolveKind("function failed")
+)
+
+type resolveKind string
+
+func (kind resolveKind) Error() string {
+	return string(kind)
+}
+
+// ResolveError is a diagnostic of formatting a message. The message is
+// usually still formatted, with the offending placeable replaced by its source,
+// so callers can decide whether the partial result is acceptable.
+type ResolveError struct {
+	// Kind is one of the Err* kinds above.
+	Kind error
+	// Key is the message being formatted.
+	Key string
+	// Name is the referenced message, term, variable or function, e.g. "$count".
+	Name string
+	// Span is the byte offsets of the offending node in the resource it was
+	// parsed from. It is zero for a missing top-level message.
+	Span [2]uint
+	// Locale is the primary locale of the bundle.
+	Locale cldr.Language
+	// Err is the underlying cause, if any, e.g. the value of a recovered panic.
+	Err error
+}
+
+func (e *ResolveError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Locale, e.Key)
+	if e.Name != "" {
+		msg += fmt.Sprintf(": %s '%s'", e.Kind, e.Name)
+	} else {
+		msg += fmt.Sprintf(": %s", e.Kind)
+	}
+	if e.Span != [2]uint{} {
+		msg += fmt.Sprintf(" at %d-%d", e.Span[0], e.Span[1])
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the kind and the cause of the error.
+func (e *ResolveError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
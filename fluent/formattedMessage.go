@@ -3,6 +3,10 @@ package fluent
 type FormattedMessage struct {
 	Value      *string
 	Attributes map[string]string
+	// Revision identifies the version of the message in the source it was
+	// loaded from, if the source has revisions. It is empty for messages
+	// formatted from values that weren't loaded with one.
+	Revision string
 }
 
 // Text returns the value of the message, or the fallback if the message is nil or has no value.
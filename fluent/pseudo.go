@@ -0,0 +1,41 @@
+package fluent
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TextTransform rewrites the text elements of a pattern; see Bundle.SetTextTransform.
+type TextTransform func(text string) string
+
+// pseudoAccents maps ASCII letters to accented look-alikes that stay readable.
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// PseudoText is a TextTransform replacing ASCII letters with accented ones,
+// e.g. "Hello" becomes "Ĥéļļö", to reveal text that bypasses the bundle.
+func PseudoText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if accented, ok := pseudoAccents[r]; ok {
+			return accented
+		}
+		return r
+	}, text)
+}
+
+// PseudoMessage brackets a formatted message and lengthens it by about 30%,
+// e.g. "Ĥéļļö ŵöŕļð" becomes "[Ĥéļļö ŵöŕļð !!!]", to reveal truncation and
+// messages concatenated from several lookups.
+func PseudoMessage(message string) string {
+	padding := utf8.RuneCountInString(message) * 3 / 10
+	if padding == 0 {
+		padding = 1
+	}
+	return "[" + message + " " + strings.Repeat("!", padding) + "]"
+}
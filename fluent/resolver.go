@@ -1,6 +1,7 @@
 package fluent
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -32,12 +33,27 @@ var pluralCategories = map[string]plural.Form{
 // It uses context-relevant values and the initial Bundle for resolving specific values.
 type resolver struct {
 	bundle          *Bundle
+	key             string
 	primaryLanguage cldr.Language
 	params          map[string]Value
 	variables       map[string]Value
 	functions       map[string]Function
 	errors          []error
-	activeMessages  map[string]struct{}
+	// activeMessages holds the patterns being resolved, by message ID or
+	// "id.attr", to detect cyclic references.
+	activeMessages map[string]struct{}
+}
+
+// addError records a ResolveError of kind for the node at span referencing name.
+func (resolver *resolver) addError(kind error, span [2]uint, name string, err error) {
+	resolver.errors = append(resolver.errors, &ResolveError{
+		Kind:   kind,
+		Key:    resolver.key,
+		Name:   name,
+		Span:   span,
+		Locale: resolver.primaryLanguage,
+		Err:    err,
+	})
 }
 
 func (resolver *resolver) resolveExpression(expression ast.Node) Value {
@@ -83,15 +99,29 @@ func (resolver *resolver) resolveExpression(expression ast.Node) Value {
 }
 
 func (resolver *resolver) resolveMessageReference(ref *ast.MessageReference) Value {
-
 	message := resolver.bundle.messages.Get(ref.ID.Name)
 	if message == nil {
-		resolver.errors = append(resolver.errors, fmt.Errorf("unknown message '%s'", ref.ID.Name))
+		resolver.addError(ErrMissingMessage, ref.Span, ref.ID.Name, nil)
 		return &NoValue{
 			value: ref.ID.Name,
 		}
 	}
 
+	// A message's value and each of its attributes are separate patterns, so
+	// only re-entering the same one is a cycle.
+	id := ref.ID.Name
+	if ref.Attribute != nil {
+		id += "." + ref.Attribute.Name
+	}
+	if _, active := resolver.activeMessages[id]; active {
+		resolver.addError(ErrCyclicReference, ref.Span, ref.ID.Name, nil)
+		return &NoValue{
+			value: ref.ID.Name,
+		}
+	}
+	resolver.activeMessages[id] = struct{}{}
+	defer delete(resolver.activeMessages, id)
+
 	if ref.Attribute != nil {
 		var attribute *ast.Attribute
 		for _, attr := range message.Attributes {
@@ -101,7 +131,7 @@ func (resolver *resolver) resolveMessageReference(ref *ast.MessageReference) Val
 			}
 		}
 		if attribute == nil {
-			resolver.errors = append(resolver.errors, fmt.Errorf("unknown message attribute '%s.%s'", ref.ID.Name, ref.Attribute.Name))
+			resolver.addError(ErrMissingMessage, ref.Span, ref.ID.Name+"."+ref.Attribute.Name, nil)
 			return &NoValue{
 				value: ref.ID.Name + "." + ref.Attribute.Name,
 			}
@@ -122,11 +152,25 @@ func (resolver *resolver) resolveMessageReference(ref *ast.MessageReference) Val
 func (resolver *resolver) resolveTermReference(ref *ast.TermReference) Value {
 	term := resolver.bundle.terms.Get(ref.ID.Name)
 	if term == nil {
-		resolver.errors = append(resolver.errors, fmt.Errorf("unknown term '%s'", ref.ID.Name))
+		resolver.addError(ErrMissingMessage, ref.Span, "-"+ref.ID.Name, nil)
+		return &NoValue{
+			value: ref.ID.Name,
+		}
+	}
+
+	// Terms share the map with messages, their names are prefixed with a dash.
+	id := "-" + ref.ID.Name
+	if ref.Attribute != nil {
+		id += "." + ref.Attribute.Name
+	}
+	if _, active := resolver.activeMessages[id]; active {
+		resolver.addError(ErrCyclicReference, ref.Span, "-"+ref.ID.Name, nil)
 		return &NoValue{
 			value: ref.ID.Name,
 		}
 	}
+	resolver.activeMessages[id] = struct{}{}
+	defer delete(resolver.activeMessages, id)
 
 	if ref.Attribute != nil {
 		var attribute *ast.Attribute
@@ -137,7 +181,7 @@ func (resolver *resolver) resolveTermReference(ref *ast.TermReference) Value {
 			}
 		}
 		if attribute == nil {
-			resolver.errors = append(resolver.errors, fmt.Errorf("unknown term attribute '%s.%s'", ref.ID.Name, ref.Attribute.Name))
+			resolver.addError(ErrMissingMessage, ref.Span, "-"+ref.ID.Name+"."+ref.Attribute.Name, nil)
 			return &NoValue{
 				value: ref.ID.Name + "." + ref.Attribute.Name,
 			}
@@ -183,7 +227,7 @@ func (resolver *resolver) resolveVariableReference(ref *ast.VariableReference) V
 		}
 	}
 	if variable == nil {
-		resolver.errors = append(resolver.errors, fmt.Errorf("unknown variable '$%s'", ref.ID.Name))
+		resolver.addError(ErrUnknownVariable, ref.Span, "$"+ref.ID.Name, nil)
 		return &NoValue{
 			value: "$" + ref.ID.Name,
 		}
@@ -194,14 +238,40 @@ func (resolver *resolver) resolveVariableReference(ref *ast.VariableReference) V
 func (resolver *resolver) resolveFunctionReference(ref *ast.FunctionReference) Value {
 	function := resolver.functions[ref.ID.Name]
 	if function == nil {
-		resolver.errors = append(resolver.errors, fmt.Errorf("unknown function '%s'", ref.ID.Name))
+		resolver.addError(ErrUnknownFunction, ref.Span, ref.ID.Name, nil)
 		return &NoValue{
 			value: ref.ID.Name,
 		}
 	}
 
 	positional, named := resolver.assembleArguments(ref.Arguments)
-	return function(positional, named, resolver.primaryLanguage)
+	value := resolver.callFunction(ref, function, positional, named)
+	if value == nil {
+		return &NoValue{
+			value: ref.ID.Name,
+		}
+	}
+	return value
+}
+
+// callFunction calls function, recording an ErrFunctionFailed if it panics or
+// returns no value. It returns nil if the function panicked.
+func (resolver *resolver) callFunction(ref *ast.FunctionReference, function Function, positional []Value, named map[string]Value) (value Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			resolver.addError(ErrFunctionFailed, ref.Span, ref.ID.Name, fmt.Errorf("panic: %v", r))
+			value = nil
+		}
+	}()
+
+	value = function(positional, named, resolver.primaryLanguage)
+	switch v := value.(type) {
+	case nil:
+		resolver.addError(ErrFunctionFailed, ref.Span, ref.ID.Name, nil)
+	case *NoValue:
+		resolver.addError(ErrFunctionFailed, ref.Span, ref.ID.Name, errors.New(v.value))
+	}
+	return value
 }
 
 func (resolver *resolver) resolveSelectExpression(ref *ast.SelectExpression) Value {
@@ -276,7 +346,11 @@ func (resolver *resolver) resolvePattern(pattern *ast.Pattern) Value {
 	result := ""
 	for _, element := range pattern.Elements {
 		if text, ok := element.(*ast.Text); ok {
-			result += text.Value
+			if resolver.bundle.transform != nil {
+				result += resolver.bundle.transform(text.Value)
+			} else {
+				result += text.Value
+			}
 			continue
 		}
 		result += resolver.resolveExpression(element.(*ast.Placeable).Expression).String()
@@ -0,0 +1,110 @@
+package fluent
+
+import (
+	"unsafe"
+
+	"github.com/summit-fi/wordsdk-go/fluent/parser/ast"
+)
+
+// Size estimates the memory held by the bundle's messages and terms in bytes:
+// the AST nodes, the strings they reference and the map entries holding them.
+func (bundle *Bundle) Size() int {
+	size := int(unsafe.Sizeof(*bundle))
+	for key, message := range bundle.messages.RetrieveAll() {
+		size += len(key) + int(unsafe.Sizeof(key)) + int(unsafe.Sizeof(message))
+		size += nodeSize(message)
+	}
+	for key, term := range bundle.terms.RetrieveAll() {
+		size += len(key) + int(unsafe.Sizeof(key)) + int(unsafe.Sizeof(term))
+		size += nodeSize(term)
+	}
+	return size
+}
+
+// nodeSize estimates the memory held by an AST node and its children.
+func nodeSize(node ast.Node) int {
+	switch n := node.(type) {
+	case *ast.Message:
+		if n == nil {
+			return 0
+		}
+		size := int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Value) + nodeSize(n.Comment)
+		for _, attr := range n.Attributes {
+			size += int(unsafe.Sizeof(attr)) + nodeSize(attr)
+		}
+		return size
+	case *ast.Term:
+		if n == nil {
+			return 0
+		}
+		size := int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Value) + nodeSize(n.Comment)
+		for _, attr := range n.Attributes {
+			size += int(unsafe.Sizeof(attr)) + nodeSize(attr)
+		}
+		return size
+	case *ast.Attribute:
+		if n == nil {
+			return 0
+		}
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Value)
+	case *ast.Pattern:
+		if n == nil {
+			return 0
+		}
+		size := int(unsafe.Sizeof(*n))
+		for _, element := range n.Elements {
+			size += int(unsafe.Sizeof(element)) + nodeSize(element)
+		}
+		return size
+	case *ast.Identifier:
+		if n == nil {
+			return 0
+		}
+		return int(unsafe.Sizeof(*n)) + len(n.Name)
+	case *ast.Comment:
+		if n == nil {
+			return 0
+		}
+		return int(unsafe.Sizeof(*n)) + len(n.Content)
+	case *ast.Text:
+		return int(unsafe.Sizeof(*n)) + len(n.Value)
+	case *ast.Placeable:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.Expression)
+	case *ast.StringLiteral:
+		return int(unsafe.Sizeof(*n)) + len(n.Value)
+	case *ast.NumberLiteral:
+		return int(unsafe.Sizeof(*n)) + len(n.Value)
+	case *ast.MessageReference:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Attribute)
+	case *ast.TermReference:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Attribute) + nodeSize(n.Arguments)
+	case *ast.VariableReference:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.ID)
+	case *ast.FunctionReference:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.ID) + nodeSize(n.Arguments)
+	case *ast.CallArguments:
+		if n == nil {
+			return 0
+		}
+		size := int(unsafe.Sizeof(*n))
+		for _, arg := range n.Positional {
+			size += int(unsafe.Sizeof(arg)) + nodeSize(arg)
+		}
+		for _, arg := range n.Named {
+			size += int(unsafe.Sizeof(arg)) + nodeSize(arg)
+		}
+		return size
+	case *ast.NamedArgument:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.Name) + nodeSize(n.Value)
+	case *ast.SelectExpression:
+		size := int(unsafe.Sizeof(*n)) + nodeSize(n.Selector)
+		for _, variant := range n.Variants {
+			size += int(unsafe.Sizeof(variant)) + nodeSize(variant)
+		}
+		return size
+	case *ast.Variant:
+		return int(unsafe.Sizeof(*n)) + nodeSize(n.Key) + nodeSize(n.Value)
+	default:
+		return 0
+	}
+}
@@ -1,6 +1,8 @@
 package fluent
 
 import (
+	"errors"
+
 	"github.com/summit-fi/wordsdk-go/fluent/cldr"
 )
 
@@ -14,6 +16,15 @@ type Value interface {
 	//Time() time.Time
 }
 
+// Valuer is implemented by types that provide their own Value when passed as
+// a variable, e.g. a money type formatting itself as a NumberValue.
+type Valuer interface {
+	FluentValue() Value
+}
+
+// ErrUnsupportedValue is reported for variables whose type can't be converted to a Value.
+var ErrUnsupportedValue = errors.New("unsupported variable type")
+
 // StringValue wraps a string in order to comply with the Value API
 type StringValue struct {
 	Value string
@@ -6,6 +6,7 @@ require (
 	github.com/boltegg/intl v0.0.0-20260314145440-a9402bea3264
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/sync v0.17.0
 	golang.org/x/text v0.29.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -19,5 +20,4 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/yaa110/go-persian-calendar v1.2.2 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
-	golang.org/x/sync v0.17.0 // indirect
 )
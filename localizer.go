@@ -0,0 +1,187 @@
+package word
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// Localizer binds the client to one locale so callers don't pass the language
+// to every lookup. The locale is checked once, when the Localizer is created;
+// its bundle is looked up in the client's current catalog on every lookup, so
+// synced changes are served, and fallback locales are consulted for keys the
+// bundle lacks. A nil *Localizer is valid and returns keys unchanged.
+type Localizer struct {
+	client  *Client
+	lang    cldr.Language
+	dynamic bool
+	reveal  RevealMode
+}
+
+// Localizer returns a Localizer for lang, which may be spelled en_US or en-US.
+// It fails with ErrUnknownLocale if the client holds no bundle for lang.
+func (c *Client) Localizer(lang string) (*Localizer, error) {
+	cat := c.catalog()
+	l := cldr.Language(lang)
+	bundle := cat.bundle(l)
+	if bundle == nil {
+		l = cldr.Language(strings.ReplaceAll(lang, "-", "_"))
+		bundle = cat.bundle(l)
+	}
+	if bundle == nil && !c.sharedHasLocale(l) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLocale, lang)
+	}
+
+	return &Localizer{
+		client: c,
+		lang:   l,
+	}, nil
+}
+
+// Lang returns the locale the Localizer is bound to.
+func (l *Localizer) Lang() cldr.Language {
+	if l == nil {
+		return ""
+	}
+	return l.lang
+}
+
+// Dynamic returns a copy of the Localizer whose lookups go through
+// DynamicContent, so keys missing locally are fetched from the source.
+func (l *Localizer) Dynamic() *Localizer {
+	if l == nil {
+		return nil
+	}
+	dynamic := *l
+	dynamic.dynamic = true
+	return &dynamic
+}
+
+func (l *Localizer) T(key string) string {
+	return l.TContext(context.Background(), key)
+}
+
+func (l *Localizer) TContext(ctx context.Context, key string) string {
+	message, _ := l.TEContext(ctx, key)
+	return message
+}
+
+func (l *Localizer) TA(key string, args any) string {
+	return l.TAContext(context.Background(), key, args)
+}
+
+func (l *Localizer) TAContext(ctx context.Context, key string, args any) string {
+	message, _ := l.TAEContext(ctx, key, args)
+	return message
+}
+
+// TE is T returning the diagnostics of the lookup as well; see Client.TE.
+func (l *Localizer) TE(key string) (string, error) {
+	return l.TEContext(context.Background(), key)
+}
+
+func (l *Localizer) TEContext(ctx context.Context, key string) (string, error) {
+	return l.TAEContext(ctx, key, nil)
+}
+
+// TAE is TA returning the diagnostics of the lookup as well; see Client.TE.
+func (l *Localizer) TAE(key string, args any) (string, error) {
+	return l.TAEContext(context.Background(), key, args)
+}
+
+func (l *Localizer) TAEContext(ctx context.Context, key string, args any) (string, error) {
+	if l == nil || l.client == nil {
+		return key, &fluent.ResolveError{Kind: fluent.ErrMissingMessage, Key: key}
+	}
+	ctx = l.revealContext(ctx)
+	if l.dynamic {
+		return l.client.Dynamic().TAEContext(ctx, string(l.lang), key, args)
+	}
+	l.client.metrics.Lookup(l.lang)
+
+	bundle := l.bundleFor(key)
+
+	var message string
+	var err error
+	if bundle == nil {
+		message, err = l.client.missingError(string(l.lang), key, args)
+	} else {
+		message, err = l.client.formatMessageError(bundle, key, fluent.WithArgs(args))
+	}
+	return l.client.reveal(ctx, key, l.lang, bundle, message), err
+}
+
+// TM returns the value and attributes of the message key; see Client.TM.
+func (l *Localizer) TM(key string, args any) *fluent.FormattedMessage {
+	return l.TMContext(context.Background(), key, args)
+}
+
+func (l *Localizer) TMContext(ctx context.Context, key string, args any) *fluent.FormattedMessage {
+	if l == nil || l.client == nil {
+		return &fluent.FormattedMessage{Value: &key, Attributes: map[string]string{}}
+	}
+	if l.dynamic {
+		return l.client.Dynamic().TMContext(ctx, string(l.lang), key, args)
+	}
+	l.client.metrics.Lookup(l.lang)
+
+	bundle := l.bundleFor(key)
+	if bundle == nil {
+		return l.client.missingMessage(string(l.lang), key, args)
+	}
+	return l.client.formatFullMessage(bundle, key, fluent.WithArgs(args))
+}
+
+// Attr formats the attribute attr of the message key, e.g. the .placeholder of
+// an input label, like Client.TAttr. If the message or the attribute is
+// missing, it returns the MissingKeyHandler's replacement for key.attr or
+// key.attr itself.
+func (l *Localizer) Attr(key, attr string, args any) string {
+	return l.AttrContext(context.Background(), key, attr, args)
+}
+
+func (l *Localizer) AttrContext(ctx context.Context, key, attr string, args any) string {
+	if l == nil || l.client == nil {
+		return key + "." + attr
+	}
+	ctx = l.revealContext(ctx)
+	message := l.TMContext(ctx, key, args)
+	value := l.client.attr(string(l.lang), key, attr, args, message)
+
+	mode := RevealFromContext(ctx)
+	if mode == RevealOff {
+		return value
+	}
+	if _, ok := message.Attributes[attr]; !ok {
+		return l.client.reveal(ctx, key, l.lang, nil, value)
+	}
+	if bundle := l.bundleFor(key); bundle != nil {
+		return l.client.reveal(ctx, key, l.lang, bundle, value)
+	}
+	// Only a value fetched from the dynamic source has no bundle.
+	return revealMessage(mode, Revealed{Key: key, Locale: l.lang, Origin: OriginDynamic, Text: value})
+}
+
+// bundleFor returns the bundle of the Localizer's locale in the current
+// catalog if it has key, otherwise the bundle of the first fallback locale
+// that has it. Bundles are never kept, so evicted locales are freed.
+func (l *Localizer) bundleFor(key string) *fluent.Bundle {
+	return l.client.resolveBundle(l.lang, key)
+}
+
+type localizerKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext returns the Localizer stored in ctx by NewContext or Middleware.
+// If there is none, it returns nil, which is still safe to call.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return l
+}
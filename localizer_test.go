@@ -0,0 +1,130 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_Localizer(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": "hello = Hello\nonly_en = Only English\n",
+		"uk_UA": "hello = Привіт, { $name }!\nsearch = Пошук\n    .placeholder = Введіть запит\n",
+	})
+	defer c.Close(context.Background())
+
+	if _, err := c.Localizer("uk_AU"); !errors.Is(err, ErrUnknownLocale) {
+		t.Fatalf("Localizer(uk_AU) error = %v, want ErrUnknownLocale", err)
+	}
+
+	l, err := c.Localizer("uk-UA")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+	if l.Lang() != cldr.LanguageUkUa {
+		t.Errorf("Lang() = %q, want %q", l.Lang(), cldr.LanguageUkUa)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"TA", l.TA("hello", map[string]any{"name": "Олена"}), "Привіт, Олена!"},
+		{"fallback", l.T("only_en"), "Only English"},
+		{"attribute", l.Attr("search", "placeholder", nil), "Введіть запит"},
+		{"missing attribute", l.Attr("search", "title", nil), "search.title"},
+		{"dynamic", l.Dynamic().T("search"), "Пошук"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizer_Attr(t *testing.T) {
+	var missing []string
+	src := &dynamicStub{
+		stubSource: stubSource{
+			objects:  []source.Object{{LocaleCode: "en_US", Key: "local", Value: "Local\n    .title = Local title"}},
+			checksum: "v1",
+		},
+		value: "Remote\n.title = Remote title",
+	}
+	sdk, err := NewClient(&Config{
+		Source:        src,
+		DefaultLocale: cldr.LanguageEnUS,
+		MissingKeyHandler: func(lang, key string, args any, caller string) (string, bool) {
+			missing = append(missing, lang+":"+key)
+			return "", false
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+
+	l, err := sdk.Localizer("en_US")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"static", l.Attr("local", "title", nil), "Local title"},
+		{"missing attribute", l.Attr("local", "aria-label", nil), "local.aria-label"},
+		{"dynamic", l.Dynamic().Attr("remote", "title", nil), "Remote title"},
+		{"reveal", l.Reveal(RevealAnnotate).Attr("local", "title", nil), "⟦local|en_US|static⟧Local title⟦/local⟧"},
+		{"reveal dynamic", l.Dynamic().Reveal(RevealAnnotate).Attr("remote", "title", nil), "⟦remote|en_US|dynamic⟧Remote title⟦/remote⟧"},
+		{"reveal missing", l.Reveal(RevealAnnotate).Attr("local", "alt", nil), "⟦local|en_US|missing⟧local.alt⟦/local⟧"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+
+	if want := []string{"en_US:local.aria-label", "en_US:local.alt"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing keys = %v, want %v", missing, want)
+	}
+}
+
+func TestLocalizer_ServesSyncedCatalog(t *testing.T) {
+	c, paths := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "changed = Before\nremoved = Removed\n",
+	})
+	defer c.Close(context.Background())
+
+	l, err := c.Localizer("en_US")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+	if got := l.T("changed"); got != "Before" {
+		t.Fatalf("T(changed) = %q, want %q", got, "Before")
+	}
+
+	if err := os.WriteFile(paths["en_US"], []byte("changed = After\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	c.syncTranslations(context.Background())
+
+	if got := l.T("changed"); got != "After" {
+		t.Errorf("T(changed) = %q, want the synced value", got)
+	}
+	if got := l.T("removed"); got != "removed" {
+		t.Errorf("T(removed) = %q, want the key", got)
+	}
+}
@@ -1,6 +1,14 @@
 package word
 
-import "log"
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
 
 type LoggerLevel string
 
@@ -16,6 +24,20 @@ type Logger interface {
 	Debugf(format string, args ...interface{})
 }
 
+// AttrLogger is implemented by loggers that accept structured attributes, such
+// as SlogLogger. The client logs its events through it when available, with
+// attributes like lang, key, source, checksum and duration; other loggers get
+// the attributes appended to the message as key=value pairs.
+type AttrLogger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// levelEnabler is implemented by loggers that can tell whether a level is logged,
+// which lets the client skip building messages that would be discarded.
+type levelEnabler interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+}
+
 type DefaultLogger struct {
 	LoggerLevel
 }
@@ -36,6 +58,18 @@ func (l *DefaultLogger) Debugf(format string, args ...interface{}) {
 	}
 }
 
+// Enabled reports whether messages of level are logged.
+func (l *DefaultLogger) Enabled(_ context.Context, level slog.Level) bool {
+	switch {
+	case level >= slog.LevelError:
+		return true
+	case level >= slog.LevelInfo:
+		return l.LoggerLevel == LogLevelInfo || l.LoggerLevel == LogLevelDebug
+	default:
+		return l.LoggerLevel == LogLevelDebug
+	}
+}
+
 func (c *Client) SetLogger(logger Logger) {
 	c.logger = logger
 }
@@ -43,3 +77,114 @@ func (c *Client) SetLogger(logger Logger) {
 func (l *DefaultLogger) SetLogLevel(level LoggerLevel) {
 	l.LoggerLevel = level
 }
+
+// SlogLogger adapts a *slog.Logger to Logger. Events logged by the client carry
+// their fields as slog attributes.
+type SlogLogger struct {
+	*slog.Logger
+}
+
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{Logger: logger}
+}
+
+func (l *SlogLogger) Errorf(format string, args ...interface{}) {
+	l.Logger.Error(fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Infof(format string, args ...interface{}) {
+	l.Logger.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Debugf(format string, args ...interface{}) {
+	l.Logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// log logs msg with attrs through the client's logger.
+func (c *Client) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	logAttrs(c.logger, level, msg, attrs...)
+}
+
+// logLookup logs an event emitted by a lookup, such as a miss. These are
+// limited to Config.LookupLogLimit per second; the number of messages dropped
+// is added to the next one logged as the suppressed attribute.
+func (c *Client) logLookup(level slog.Level, msg string, attrs ...slog.Attr) {
+	if l, ok := c.logger.(levelEnabler); ok && !l.Enabled(context.Background(), level) {
+		return
+	}
+	ok, suppressed := c.lookupLogs.allow(time.Now())
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, slog.Int("suppressed", suppressed))
+	}
+	c.log(level, msg, attrs...)
+}
+
+func logAttrs(logger Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if l, ok := logger.(AttrLogger); ok {
+		l.LogAttrs(context.Background(), level, msg, attrs...)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(msg)
+	for _, attr := range attrs {
+		sb.WriteByte(' ')
+		sb.WriteString(attr.String())
+	}
+	switch {
+	case level >= slog.LevelError:
+		logger.Errorf("%s", sb.String())
+	case level >= slog.LevelInfo:
+		logger.Infof("%s", sb.String())
+	default:
+		logger.Debugf("%s", sb.String())
+	}
+}
+
+// sourceName names the client's source in log attributes, e.g. *source.Remote.
+func (c *Client) sourceName() string {
+	return fmt.Sprintf("%T", c.source)
+}
+
+// defaultLookupLogLimit is the number of lookup messages logged per second when
+// Config.LookupLogLimit is unset.
+const defaultLookupLogLimit = 10
+
+// logLimiter allows up to limit messages per one-second window.
+type logLimiter struct {
+	limit int
+
+	mu         sync.Mutex
+	window     time.Time
+	count      int
+	suppressed int
+}
+
+// allow reports whether a message may be logged at now and, if so, how many
+// were suppressed since the last one allowed. A negative limit allows all.
+func (l *logLimiter) allow(now time.Time) (bool, int) {
+	if l.limit < 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.window) >= time.Second {
+		l.window = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		l.suppressed++
+		return false, 0
+	}
+	l.count++
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return true, suppressed
+}
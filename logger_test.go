@@ -0,0 +1,108 @@
+package word
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_SlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c, _ := tempFtlClient(t, Config{Logger: NewSlogLogger(logger)}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	buf.Reset()
+	c.T("en_US", "unknown")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log output %q is not a single JSON record: %v", buf.String(), err)
+	}
+	if record["level"] != "DEBUG" || record["lang"] != "en_US" || record["key"] != "unknown" {
+		t.Errorf("miss record = %v, want DEBUG with lang en_US and key unknown", record)
+	}
+
+	buf.Reset()
+	c.syncTranslations(context.Background())
+	if !strings.Contains(buf.String(), `"source":"*source.Ftl"`) || !strings.Contains(buf.String(), `"checksum":`) {
+		t.Errorf("sync record = %s, want source and checksum attributes", buf.String())
+	}
+
+	buf.Reset()
+	if err := c.Dynamic().SaveTranslation("en_US", "greeting", "Secret greeting"); err != nil {
+		t.Fatalf("SaveTranslation() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"lang":"en_US","key":"greeting"`) || strings.Contains(buf.String(), "Secret greeting") {
+		t.Errorf("save record = %s, want lang and key attributes without the value", buf.String())
+	}
+}
+
+func TestClient_LookupLogLimit(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c, _ := tempFtlClient(t, Config{Logger: NewSlogLogger(logger), LookupLogLimit: 2}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	buf.Reset()
+	for i := 0; i < 5; i++ {
+		c.T("en_US", "unknown")
+	}
+	if n := strings.Count(buf.String(), "Message not found"); n != 2 {
+		t.Errorf("logged %d misses, want 2:\n%s", n, buf.String())
+	}
+}
+
+func TestLogLimiter(t *testing.T) {
+	l := &logLimiter{limit: 1}
+	now := time.Now()
+
+	if ok, _ := l.allow(now); !ok {
+		t.Fatal("first message not allowed")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow(now.Add(time.Millisecond)); ok {
+			t.Fatal("message over the limit allowed")
+		}
+	}
+	ok, suppressed := l.allow(now.Add(time.Second))
+	if !ok || suppressed != 3 {
+		t.Errorf("allow() in the next window = %v, %d, want true, 3", ok, suppressed)
+	}
+
+	unlimited := &logLimiter{limit: -1}
+	for i := 0; i < 100; i++ {
+		if ok, _ := unlimited.allow(now); !ok {
+			t.Fatal("unlimited limiter refused a message")
+		}
+	}
+}
+
+func TestDefaultLogger_AttrsInMessage(t *testing.T) {
+	var got string
+	logger := &recordingLogger{debugf: func(s string) { got = s }}
+	logAttrs(logger, slog.LevelDebug, "Message not found", slog.String("lang", "en_US"), slog.String("key", "hello"))
+	if got != "Message not found lang=en_US key=hello" {
+		t.Errorf("Debugf message = %q", got)
+	}
+}
+
+type recordingLogger struct {
+	debugf func(string)
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {}
+func (l *recordingLogger) Infof(format string, args ...interface{})  {}
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.debugf(args[0].(string))
+}
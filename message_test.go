@@ -0,0 +1,135 @@
+package word
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_TMAndTAttr(t *testing.T) {
+	var missing []string
+	c, _ := tempFtlClient(t, Config{
+		DefaultLocale: cldr.LanguageEnUS,
+		MissingKeyHandler: func(lang, key string, args any, caller string) (string, bool) {
+			missing = append(missing, lang+":"+key)
+			return "", false
+		},
+	}, map[string]string{
+		"uk_UA": "email = Пошта\n    .placeholder = Ваша пошта, { $name }\n",
+		"en_US": "email = Email\n    .placeholder = Your email\n    .aria-label = Email address\nsubmit = Send\n    .title = Send the form\n",
+	})
+	defer c.Close(context.Background())
+
+	m := c.TM("uk_UA", "email", map[string]any{"name": "Олена"})
+	if got := m.Text(""); got != "Пошта" {
+		t.Errorf("TM().Text() = %q, want %q", got, "Пошта")
+	}
+	if got := m.Attr("placeholder", ""); got != "Ваша пошта, Олена" {
+		t.Errorf("TM().Attr(placeholder) = %q, want %q", got, "Ваша пошта, Олена")
+	}
+
+	tests := []struct {
+		name            string
+		lang, key, attr string
+		want            string
+	}{
+		{"own locale", "uk_UA", "email", "placeholder", "Ваша пошта, {$name}"},
+		{"fallback locale", "uk_UA", "submit", "title", "Send the form"},
+		// The message is resolved as a whole, like T; its attributes don't fall back.
+		{"attribute missing in the served message", "uk_UA", "email", "aria-label", "email.aria-label"},
+		{"missing message", "uk_UA", "unknown", "title", "unknown.title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.TAttr(tt.lang, tt.key, tt.attr, nil); got != tt.want {
+				t.Errorf("TAttr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	want := []string{"uk_UA:email.aria-label", "uk_UA:unknown", "uk_UA:unknown.title"}
+	if len(missing) != len(want) {
+		t.Fatalf("missing keys = %v, want %v", missing, want)
+	}
+	for i := range want {
+		if missing[i] != want[i] {
+			t.Errorf("missing keys = %v, want %v", missing, want)
+			break
+		}
+	}
+
+	if m := c.TM("uk_UA", "unknown", nil); m.Text("") != "unknown" || len(m.Attributes) != 0 {
+		t.Errorf("TM(missing) = %q %v, want the key without attributes", m.Text(""), m.Attributes)
+	}
+}
+
+func TestClient_SelfReferences(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": strings.Join([]string{
+			"login = { login.label } now",
+			"    .label = Sign in",
+			"btn = Go",
+			"    .title = { btn } button",
+			"ring = Ring",
+			"    .a = { ring.b }",
+			"    .b = { ring.a }",
+		}, "\n") + "\n",
+	})
+	defer c.Close(context.Background())
+
+	// A message's value and attributes may refer to each other.
+	if got, err := c.TAE("en_US", "login", nil); got != "Sign in now" || err != nil {
+		t.Errorf("TAE(login) = %q, %v, want %q, nil", got, err, "Sign in now")
+	}
+	if got := c.TAttr("en_US", "btn", "title", nil); got != "Go button" {
+		t.Errorf("TAttr(btn, title) = %q, want %q", got, "Go button")
+	}
+
+	// Re-entering the same attribute is still a cycle.
+	if got := c.TAttr("en_US", "ring", "a", nil); got != "{ring}" {
+		t.Errorf("TAttr(ring, a) = %q, want %q", got, "{ring}")
+	}
+}
+
+// dynamicStub serves one dynamic value on top of stubSource.
+type dynamicStub struct {
+	stubSource
+	value string
+}
+
+func (s *dynamicStub) LoadOneDynamic(accessKey, lang, key string) (string, error) {
+	return s.value, nil
+}
+
+func TestDynamicContent_TMAndTAttr(t *testing.T) {
+	src := &dynamicStub{
+		stubSource: stubSource{
+			objects: []source.Object{
+				{LocaleCode: "en_US", Key: "local", Value: "Local\n    .title = Local title"},
+			},
+			checksum: "v1",
+		},
+		value: "Remote { $name }\n.title = Remote title",
+	}
+	sdk, err := NewClient(&Config{Source: src})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+	d := sdk.Dynamic()
+
+	if got := d.TAttr("en_US", "local", "title", nil); got != "Local title" {
+		t.Errorf("TAttr(local) = %q, want %q", got, "Local title")
+	}
+
+	m := d.TM("en_US", "remote", map[string]any{"name": "Olena"})
+	if got := m.Text(""); got != "Remote Olena" {
+		t.Errorf("TM(remote).Text() = %q, want %q", got, "Remote Olena")
+	}
+	if got := d.TAttr("en_US", "remote", "title", nil); got != "Remote title" {
+		t.Errorf("TAttr(remote) = %q, want %q", got, "Remote title")
+	}
+}
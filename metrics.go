@@ -0,0 +1,68 @@
+package word
+
+import (
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// Metrics receives measurements of the SDK at work. Implementations must be
+// safe for concurrent use and fast, as most methods are called on every lookup.
+// See PrometheusMetrics for an implementation exposing them to Prometheus.
+type Metrics interface {
+	// Lookup is called for every translation lookup, with the requested locale
+	// or UnknownLocale if the client doesn't serve it. The same goes for the
+	// locales passed to the other methods.
+	Lookup(lang cldr.Language)
+	// Miss is called for every lookup that found no message in lang or its fallbacks.
+	Miss(lang cldr.Language)
+	// Fallback is called for every message served from a fallback locale.
+	Fallback(requested, served cldr.Language)
+	// DynamicFetch is called after every request for a dynamic value made to the source.
+	DynamicFetch(lang cldr.Language, duration time.Duration, err error)
+	// Sync is called after every sync of the static catalog.
+	Sync(duration time.Duration, outcome SyncOutcome)
+	// BundleSize is called with the estimated size of a locale's bundle whenever
+	// it is built, rebuilt or evicted, in which case bytes is 0.
+	BundleSize(lang cldr.Language, bytes int)
+	// ResolverError is called for every message whose formatting reported errors,
+	// e.g. an unknown variable.
+	ResolverError(lang cldr.Language)
+}
+
+// UnknownLocale is the locale passed to Metrics for lookups in locales the
+// catalog doesn't have, so that callers can't create a series per string.
+const UnknownLocale cldr.Language = "unknown"
+
+// metricLocale returns lang if the client serves it or has fallbacks for it,
+// and UnknownLocale otherwise.
+func (c *Client) metricLocale(lang cldr.Language) cldr.Language {
+	if c.catalog().entries[lang] != nil || c.sharedHasLocale(lang) {
+		return lang
+	}
+	if _, ok := c.fallbacks[lang]; ok {
+		return lang
+	}
+	return UnknownLocale
+}
+
+// SyncOutcome is the result of a sync of the static catalog.
+type SyncOutcome string
+
+const (
+	SyncUpdated   SyncOutcome = "updated"
+	SyncUnchanged SyncOutcome = "unchanged"
+	SyncFailed    SyncOutcome = "failed"
+)
+
+// NopMetrics is a Metrics that discards everything. It is used when
+// Config.Metrics is nil.
+type NopMetrics struct{}
+
+func (NopMetrics) Lookup(cldr.Language)                             {}
+func (NopMetrics) Miss(cldr.Language)                               {}
+func (NopMetrics) Fallback(cldr.Language, cldr.Language)            {}
+func (NopMetrics) DynamicFetch(cldr.Language, time.Duration, error) {}
+func (NopMetrics) Sync(time.Duration, SyncOutcome)                  {}
+func (NopMetrics) BundleSize(cldr.Language, int)                    {}
+func (NopMetrics) ResolverError(cldr.Language)                      {}
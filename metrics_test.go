@@ -0,0 +1,128 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_Metrics(t *testing.T) {
+	metrics := NewPrometheusMetrics("")
+	src := &stubSource{
+		objects: []source.Object{
+			{LocaleCode: "en_US", Key: "hello", Value: "Hello, { $name }!"},
+			{LocaleCode: "uk_UA", Key: "bye", Value: "Бувай"},
+		},
+		checksum: "v1",
+	}
+	sdk, err := NewClient(&Config{
+		Source:        src,
+		DefaultLocale: "en_US",
+		Metrics:       metrics,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	c := sdk.(*Client)
+	defer c.Close(context.Background())
+
+	c.T("uk_UA", "bye")
+	c.T("uk_UA", "hello")   // served from en_US with an unknown variable
+	c.T("uk_UA", "unknown") // missing
+	c.T("xx_YY", "bye")     // locales the client doesn't have share one label
+	c.T("../../etc", "bye")
+
+	src.mu.Lock()
+	src.err = errors.New("word api is down")
+	src.mu.Unlock()
+	c.syncTranslations(context.Background())
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE wordsdk_lookups_total counter\n",
+		`wordsdk_lookups_total{locale="uk_UA"} 3`,
+		`wordsdk_misses_total{locale="uk_UA"} 1`,
+		`wordsdk_lookups_total{locale="unknown"} 2`,
+		`wordsdk_fallbacks_total{requested="uk_UA",served="en_US"} 1`,
+		`wordsdk_resolver_errors_total{locale="en_US"} 1`,
+		`wordsdk_syncs_total{outcome="unchanged"} 1`,
+		`wordsdk_syncs_total{outcome="failed"} 1`,
+		`wordsdk_sync_duration_seconds_bucket{outcome="failed",le="+Inf"} 1`,
+		`wordsdk_sync_duration_seconds_count{outcome="failed"} 1`,
+		`wordsdk_bundle_size_bytes{locale="en_US"} `,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "xx_YY") || strings.Contains(body, "etc") {
+		t.Errorf("metrics have a series per requested locale:\n%s", body)
+	}
+}
+
+func TestPrometheusMetrics_Histogram(t *testing.T) {
+	metrics := NewPrometheusMetrics("app")
+	metrics.DynamicFetch(cldr.Language("en_US"), 0, nil)
+	metrics.DynamicFetch(cldr.Language("en_US"), 3e9, errors.New("timeout"))
+
+	var sb strings.Builder
+	if _, err := metrics.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	body := sb.String()
+
+	for _, want := range []string{
+		`app_dynamic_fetch_duration_seconds_bucket{locale="en_US",le="0.005"} 1`,
+		`app_dynamic_fetch_duration_seconds_bucket{locale="en_US",le="2.5"} 1`,
+		`app_dynamic_fetch_duration_seconds_bucket{locale="en_US",le="5"} 2`,
+		`app_dynamic_fetch_duration_seconds_bucket{locale="en_US",le="+Inf"} 2`,
+		`app_dynamic_fetch_duration_seconds_sum{locale="en_US"} 3`,
+		`app_dynamic_fetch_errors_total{locale="en_US"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// blockingWriter signals entered on its first write and then waits for release.
+type blockingWriter struct {
+	entered, release chan struct{}
+	once             sync.Once
+}
+
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.entered) })
+	<-w.release
+	return len(b), nil
+}
+
+func TestPrometheusMetrics_SlowWriter(t *testing.T) {
+	p := NewPrometheusMetrics("wordsdk")
+	p.Fallback("uk_UA", "en_US")
+
+	w := &blockingWriter{entered: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.WriteTo(w)
+	}()
+	<-w.entered
+
+	// The writer is stuck, but metrics are still recorded.
+	p.Fallback("uk_UA", "en_US")
+	p.Sync(time.Millisecond, SyncUpdated)
+	close(w.release)
+	<-done
+}
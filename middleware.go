@@ -0,0 +1,91 @@
+package word
+
+import (
+	"net/http"
+	"strings"
+)
+
+// LocaleResolver extracts a locale preference from a request.
+// It returns an empty string when the request carries no preference.
+type LocaleResolver func(r *http.Request) string
+
+// MiddlewareOptions configures Middleware.
+type MiddlewareOptions struct {
+	// Resolvers are tried in order and the first non-empty result wins.
+	// Defaults to QueryResolver("lang"), CookieResolver("lang"), HeaderResolver
+	// and, if set, UserLocale.
+	Resolvers []LocaleResolver
+	// UserLocale returns the locale stored in the user's profile, if any.
+	UserLocale LocaleResolver
+	// Reveal, if set, returns the RevealMode of the request, e.g. RevealMarkers
+	// for translators using an in-context editor. Only enable it for trusted users.
+	Reveal func(r *http.Request) RevealMode
+}
+
+// QueryResolver reads the locale from the query parameter name.
+func QueryResolver(name string) LocaleResolver {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(name)
+	}
+}
+
+// CookieResolver reads the locale from the cookie name.
+func CookieResolver(name string) LocaleResolver {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}
+}
+
+// HeaderResolver reads the Accept-Language header.
+func HeaderResolver(r *http.Request) string {
+	return r.Header.Get("Accept-Language")
+}
+
+// Middleware stores a Localizer for the request locale in the request context,
+// where handlers retrieve it with FromContext.
+// Every resolved value is negotiated with SDK.Negotiate, so single locales in
+// either en_US or en-US spelling and full Accept-Language lists are accepted.
+func Middleware(sdk SDK, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	resolvers := opts.Resolvers
+	if len(resolvers) == 0 {
+		resolvers = []LocaleResolver{
+			QueryResolver("lang"),
+			CookieResolver("lang"),
+			HeaderResolver,
+		}
+		if opts.UserLocale != nil {
+			resolvers = append(resolvers, opts.UserLocale)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := resolveLocale(sdk, resolvers, r)
+			// If even the default locale has no bundle, the nil Localizer
+			// stored here returns keys unchanged.
+			localizer, _ := sdk.Localizer(lang)
+			ctx := r.Context()
+			if opts.Reveal != nil {
+				if mode := opts.Reveal(r); mode != RevealOff {
+					localizer = localizer.Reveal(mode)
+					ctx = WithReveal(ctx, mode)
+				}
+			}
+			ctx = NewContext(ctx, localizer)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func resolveLocale(sdk SDK, resolvers []LocaleResolver, r *http.Request) string {
+	for _, resolve := range resolvers {
+		if value := resolve(r); value != "" {
+			return string(sdk.Negotiate(strings.ReplaceAll(value, "_", "-")))
+		}
+	}
+	return string(sdk.Negotiate(""))
+}
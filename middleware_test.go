@@ -0,0 +1,61 @@
+package word
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestMiddleware(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": "hello = Hello\n",
+		"uk_UA": "hello = Привіт\n",
+		"es_CO": "hello = Hola\n",
+	})
+	defer c.Close(context.Background())
+
+	handler := Middleware(c, MiddlewareOptions{
+		UserLocale: func(r *http.Request) string { return r.Header.Get("X-User-Locale") },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).T("hello")))
+	}))
+
+	tests := []struct {
+		name   string
+		target string
+		cookie string
+		header map[string]string
+		want   string
+	}{
+		{"query", "/?lang=uk_UA", "es_CO", map[string]string{"Accept-Language": "en"}, "Привіт"},
+		{"cookie", "/", "es-CO", map[string]string{"Accept-Language": "uk"}, "Hola"},
+		{"header", "/", "", map[string]string{"Accept-Language": "uk-UA,uk;q=0.9"}, "Привіт"},
+		{"user profile", "/", "", map[string]string{"X-User-Locale": "es_CO"}, "Hola"},
+		{"default", "/", "", nil, "Hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromContext_NoLocalizer(t *testing.T) {
+	if got := FromContext(context.Background()).T("some_key"); got != "some_key" {
+		t.Errorf("T() = %q, want key", got)
+	}
+}
@@ -0,0 +1,190 @@
+package word
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// MissingKeyHandler is called when a lookup finds no message for key in lang or
+// its fallbacks. args are the arguments passed to TA, if any, and caller is the
+// file:line of the code that asked for the key. If ok is true, replacement is
+// returned to the caller instead of the key.
+type MissingKeyHandler func(lang, key string, args any, caller string) (replacement string, ok bool)
+
+// missing is called for every lookup that found no message. It returns the
+// handler's replacement or the key itself.
+func (c *Client) missing(lang, key string, args any) string {
+	c.metrics.Miss(c.metricLocale(cldr.Language(lang)))
+	c.logLookup(slog.LevelDebug, "Message not found, returning key", slog.String("lang", lang), slog.String("key", key))
+	if c.missingKeyHandler == nil {
+		return key
+	}
+	if replacement, ok := c.missingKeyHandler(lang, key, args, callerOutsideSDK()); ok {
+		return replacement
+	}
+	return key
+}
+
+const sdkPackage = "github.com/summit-fi/wordsdk-go."
+
+// callerOutsideSDK returns the file:line of the first stack frame that is not
+// part of this package, i.e. the application code that made the lookup.
+func callerOutsideSDK() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		inSDK := strings.HasPrefix(frame.Function, sdkPackage) && !strings.HasSuffix(frame.File, "_test.go")
+		if !inSDK {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// MissingKeyCollector deduplicates missing keys and periodically sends them to
+// a source.MissingReporter, such as a Remote source or a source.MissingFile.
+// Each report holds the keys missed since the previous one, with the number of
+// times they were missed in between. Use its Handle method as
+// Config.MissingKeyHandler.
+type MissingKeyCollector struct {
+	reporter source.MissingReporter
+	logger   Logger
+
+	mu      sync.Mutex
+	pending map[missingID]*source.MissingKey
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type missingID struct {
+	lang, key string
+}
+
+// NewMissingKeyCollector starts a collector that reports every interval.
+// If interval is zero or negative, keys are only reported by Report and Close.
+func NewMissingKeyCollector(reporter source.MissingReporter, interval time.Duration) *MissingKeyCollector {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &MissingKeyCollector{
+		reporter: reporter,
+		logger:   &DefaultLogger{LogLevelError},
+		pending:  make(map[missingID]*source.MissingKey),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	if interval <= 0 {
+		close(m.done)
+		return m
+	}
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Report(ctx); err != nil && ctx.Err() == nil {
+					logAttrs(m.logger, slog.LevelError, "Failed to report missing keys", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+	return m
+}
+
+// SetLogger sets the logger used for failed periodic reports.
+func (m *MissingKeyCollector) SetLogger(logger Logger) {
+	m.logger = logger
+}
+
+// Handle records a missing key. It never provides a replacement, so the key is
+// returned to the caller.
+func (m *MissingKeyCollector) Handle(lang, key string, args any, caller string) (string, bool) {
+	id := missingID{lang, key}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if k, ok := m.pending[id]; ok {
+		k.Count++
+		k.LastSeen = now
+		return "", false
+	}
+	m.pending[id] = &source.MissingKey{
+		LocaleCode: lang,
+		Key:        key,
+		Caller:     caller,
+		Count:      1,
+		FirstSeen:  now,
+		LastSeen:   now,
+	}
+	return "", false
+}
+
+// Report sends the keys collected since the last successful report.
+// On failure they are kept for the next attempt. Keys missed again while the
+// report was being sent are kept with the count of the new misses.
+func (m *MissingKeyCollector) Report(ctx context.Context) error {
+	m.mu.Lock()
+	if len(m.pending) == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	keys := make([]source.MissingKey, 0, len(m.pending))
+	for _, k := range m.pending {
+		keys = append(keys, *k)
+	}
+	m.mu.Unlock()
+
+	if err := m.reporter.ReportMissing(ctx, keys); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		id := missingID{k.LocaleCode, k.Key}
+		pending, ok := m.pending[id]
+		if !ok {
+			continue
+		}
+		if pending.Count <= k.Count {
+			delete(m.pending, id)
+			continue
+		}
+		pending.Count -= k.Count
+		pending.FirstSeen = k.LastSeen
+	}
+	return nil
+}
+
+// Close stops the periodic reports and reports what is still pending.
+func (m *MissingKeyCollector) Close(ctx context.Context) error {
+	m.closeOnce.Do(m.cancel)
+
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return m.Report(ctx)
+}
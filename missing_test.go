@@ -0,0 +1,121 @@
+package word
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_MissingKeyHandler(t *testing.T) {
+	var gotCaller string
+	c, _ := tempFtlClient(t, Config{
+		MissingKeyHandler: func(lang, key string, args any, caller string) (string, bool) {
+			gotCaller = caller
+			if key == "replaced" {
+				return "[" + lang + ":" + key + "]", true
+			}
+			return "", false
+		},
+	}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	if got := c.T("en_US", "replaced"); got != "[en_US:replaced]" {
+		t.Errorf("T() = %q, want replacement", got)
+	}
+	if !strings.Contains(gotCaller, "missing_test.go:") {
+		t.Errorf("caller = %q, want this test file", gotCaller)
+	}
+	if got := c.TA("en_US", "kept", map[string]any{"n": 1}); got != "kept" {
+		t.Errorf("TA() = %q, want key", got)
+	}
+}
+
+func TestMissingKeyCollector_ReportsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	collector := NewMissingKeyCollector(source.NewMissingFile(path), 0)
+
+	c, _ := tempFtlClient(t, Config{MissingKeyHandler: collector.Handle}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	for i := 0; i < 3; i++ {
+		c.T("en_US", "first")
+	}
+	c.T("uk_UA", "second")
+	c.T("en_US", "hello")
+
+	if err := collector.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	var keys []source.MissingKey
+	if err := json.Unmarshal(b, &keys); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("got %d keys, want 2: %s", len(keys), b)
+	}
+	if keys[0].LocaleCode != "en_US" || keys[0].Key != "first" || keys[0].Count != 3 {
+		t.Errorf("keys[0] = %+v, want en_US/first seen 3 times", keys[0])
+	}
+	if keys[1].LocaleCode != "uk_UA" || keys[1].Key != "second" || keys[1].Count != 1 {
+		t.Errorf("keys[1] = %+v, want uk_UA/second seen once", keys[1])
+	}
+}
+
+// handlingReporter records the reports and misses key once more while each one
+// is being sent.
+type handlingReporter struct {
+	collector *MissingKeyCollector
+	key       string
+	reports   [][]source.MissingKey
+}
+
+func (r *handlingReporter) ReportMissing(ctx context.Context, keys []source.MissingKey) error {
+	r.reports = append(r.reports, keys)
+	r.collector.Handle("en_US", r.key, nil, "")
+	return nil
+}
+
+func TestMissingKeyCollector_ReportsAgain(t *testing.T) {
+	reporter := &handlingReporter{key: "first"}
+	collector := NewMissingKeyCollector(reporter, 0)
+	reporter.collector = collector
+
+	collector.Handle("en_US", "first", nil, "")
+	collector.Handle("en_US", "first", nil, "")
+	if err := collector.Report(context.Background()); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	// The miss made while the first report was sent is reported next, along
+	// with the keys missed since.
+	reporter.key = "other"
+	collector.Handle("en_US", "first", nil, "")
+	if err := collector.Report(context.Background()); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	if len(reporter.reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reporter.reports))
+	}
+	if got := reporter.reports[0]; len(got) != 1 || got[0].Count != 2 {
+		t.Errorf("first report = %+v, want en_US/first seen twice", got)
+	}
+	if got := reporter.reports[1]; len(got) != 1 || got[0].Key != "first" || got[0].Count != 2 {
+		t.Errorf("second report = %+v, want en_US/first seen twice since the first report", got)
+	}
+}
@@ -0,0 +1,245 @@
+package word
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// namespaces holds the namespaced part of the catalog of a root client: the
+// static and synced dynamic values of every namespace as last loaded and the
+// clients serving the namespaces requested with Namespace.
+type namespaces struct {
+	static  map[string]catalogValues
+	dynamic map[string]catalogValues
+	clients map[string]*Client
+}
+
+// Namespace returns an SDK scoped to the objects of the namespace name, with
+// bundles of its own. Lookups of keys the namespace lacks fall back to the
+// shared namespace, the objects without a namespace, which the client itself
+// serves. Namespace("") returns the client of the shared namespace.
+//
+// Namespaces are kept up to date by the sync of the shared namespace; they
+// have no sync of their own.
+func (c *Client) Namespace(name string) SDK {
+	root := c.root()
+	if name == "" {
+		return root
+	}
+
+	root.namespacesMu.Lock()
+	defer root.namespacesMu.Unlock()
+	if ns := root.namespaces.clients[name]; ns != nil {
+		return ns
+	}
+
+	ns := root.newNamespace(name)
+	if root.namespaces.clients == nil {
+		root.namespaces.clients = make(map[string]*Client)
+	}
+	root.namespaces.clients[name] = ns
+	return ns
+}
+
+// root returns the client of the shared namespace.
+func (c *Client) root() *Client {
+	if c.shared != nil {
+		return c.shared
+	}
+	return c
+}
+
+// newNamespace creates the client of the namespace name from the values last
+// loaded for it. It is called with namespacesMu held.
+func (c *Client) newNamespace(name string) *Client {
+	src := c.source
+	if scoped, ok := src.(source.NamespaceSource); ok {
+		src = scoped.WithNamespace(name)
+	}
+
+	ns := &Client{
+		httpClient:        c.httpClient,
+		source:            src,
+		logger:            c.logger,
+		logLevel:          c.logLevel,
+		maxBackoff:        c.maxBackoff,
+		maxCacheSizeMB:    c.maxCacheSizeMB,
+		maxDynamicEntries: c.maxDynamicEntries,
+		cache: &bundleCache{
+			maxBytes:     c.cache.maxBytes,
+			metrics:      c.cache.metrics,
+			pseudo:       c.cache.pseudo,
+			pseudoSource: c.cache.pseudoSource,
+		},
+		saveStrategy:      c.saveStrategy,
+		flushBatchSize:    c.flushBatchSize,
+		fallbacks:         c.fallbacks,
+		defaultLocale:     c.defaultLocale,
+		onFallback:        c.onFallback,
+		missingKeyHandler: c.missingKeyHandler,
+		status:            c.status,
+		metrics:           c.metrics,
+		lookupLogs:        c.lookupLogs,
+		dynamicCache:      newDynamicCache(c.dynamicCache.ttl),
+		namespace:         name,
+		shared:            c,
+		ctx:               c.ctx,
+		cancel:            func() {},
+		done:              make(chan struct{}),
+	}
+	ns.dynamicContentAccessKey.Store(c.accessKey())
+	ns.cache.current = ns.catalog
+	close(ns.done)
+
+	// The values were parsed when they were loaded, so this can't fail.
+	cat, _ := newCatalog(ns.cache, nil, c.namespaces.static[name], c.namespaces.dynamic[name], nil, nil)
+	ns.current.Store(cat)
+	return ns
+}
+
+// sharedHasLocale reports whether the client is a namespace whose shared
+// namespace has a bundle for lang.
+func (c *Client) sharedHasLocale(lang cldr.Language) bool {
+	return c.shared != nil && c.shared.catalog().bundle(lang) != nil
+}
+
+// splitNamespaces separates the objects of the shared namespace from the
+// namespaced ones, which are indexed by namespace.
+func splitNamespaces(data []source.Object) ([]source.Object, map[string]catalogValues) {
+	var shared []source.Object
+	byNamespace := make(map[string][]source.Object)
+	for _, item := range data {
+		if item.Namespace == "" {
+			shared = append(shared, item)
+			continue
+		}
+		byNamespace[item.Namespace] = append(byNamespace[item.Namespace], item)
+	}
+
+	namespaced := make(map[string]catalogValues, len(byNamespace))
+	for name, items := range byNamespace {
+		namespaced[name] = newCatalogValues(items)
+	}
+	return shared, namespaced
+}
+
+// setNamespaces replaces the static values of every namespace and rebuilds the
+// changed locales of the namespace clients. With reset set, the values saved
+// through their DynamicContent are dropped as well. It returns the changes of
+// every namespace, requested or not, by namespace.
+func (c *Client) setNamespaces(static map[string]catalogValues, reset bool) map[string]map[cldr.Language]LocaleChanges {
+	c.namespacesMu.Lock()
+	defer c.namespacesMu.Unlock()
+
+	changes := make(map[string]map[cldr.Language]LocaleChanges)
+	for name, values := range static {
+		if diff := values.diff(c.namespaces.static[name]); len(diff) > 0 {
+			changes[name] = diff
+		}
+	}
+	for name, values := range c.namespaces.static {
+		if _, ok := static[name]; !ok {
+			changes[name] = catalogValues(nil).diff(values)
+		}
+	}
+
+	c.namespaces.static = static
+	for name, ns := range c.namespaces.clients {
+		values := static[name]
+		err := ns.updateCatalog(func(cur *catalog) (*catalog, error) {
+			if reset {
+				ns.resetSaved()
+				return newCatalog(ns.cache, cur, values, cur.dynamic, nil, nil)
+			}
+			rebuild := make(map[cldr.Language]struct{})
+			for l := range values.diff(cur.static) {
+				rebuild[l] = struct{}{}
+			}
+			return newCatalog(ns.cache, cur, values, cur.dynamic, cur.saved, rebuild)
+		})
+		if err != nil {
+			c.log(slog.LevelError, "Failed to update namespace", slog.String("namespace", name), slog.Any("error", err))
+		}
+	}
+	return changes
+}
+
+// setNamespacesDynamic replaces the synced dynamic values of every namespace
+// and rebuilds the changed locales of the namespace clients.
+func (c *Client) setNamespacesDynamic(dynamic map[string]catalogValues) {
+	c.namespacesMu.Lock()
+	defer c.namespacesMu.Unlock()
+
+	c.namespaces.dynamic = dynamic
+	for name, ns := range c.namespaces.clients {
+		if err := ns.setDynamic(dynamic[name], nil); err != nil {
+			c.log(slog.LevelError, "Failed to update namespace", slog.String("namespace", name), slog.Any("error", err))
+		}
+	}
+}
+
+// mergeNamespaces adds the values of static to the namespaces, as UpdateBundle does.
+func (c *Client) mergeNamespaces(static map[string]catalogValues) error {
+	c.namespacesMu.Lock()
+	defer c.namespacesMu.Unlock()
+
+	for name, values := range static {
+		if c.namespaces.static == nil {
+			c.namespaces.static = make(map[string]catalogValues)
+		}
+		c.namespaces.static[name] = c.namespaces.static[name].merge(values)
+		if ns := c.namespaces.clients[name]; ns != nil {
+			if err := ns.mergeStatic(values, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// namespaceObjects returns the static values of every namespace as source
+// objects, for the snapshot.
+func (c *Client) namespaceObjects() []source.Object {
+	c.namespacesMu.Lock()
+	defer c.namespacesMu.Unlock()
+
+	var objects []source.Object
+	for name, values := range c.namespaces.static {
+		objects = append(objects, values.namespaceObjects(name)...)
+	}
+	return objects
+}
+
+// namespaceObjects is objects with the namespace set to name.
+func (v catalogValues) namespaceObjects(name string) []source.Object {
+	objects := v.objects()
+	for i := range objects {
+		objects[i].Namespace = name
+	}
+	return objects
+}
+
+// namespaceClients returns the clients of the namespaces requested so far.
+func (c *Client) namespaceClients() []*Client {
+	c.namespacesMu.Lock()
+	defer c.namespacesMu.Unlock()
+
+	clients := make([]*Client, 0, len(c.namespaces.clients))
+	for _, ns := range c.namespaces.clients {
+		clients = append(clients, ns)
+	}
+	return clients
+}
+
+// drainNamespaces saves the pending dynamic writes of every namespace.
+func (c *Client) drainNamespaces(ctx context.Context) error {
+	for _, ns := range c.namespaceClients() {
+		if err := ns.flushWrites(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
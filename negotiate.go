@@ -0,0 +1,126 @@
+package word
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// Negotiate picks the cached locale that best matches an Accept-Language header.
+// If nothing matches, the configured default locale is returned.
+func (c *Client) Negotiate(header string) cldr.Language {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		c.logLookup(slog.LevelDebug, "Failed to parse Accept-Language",
+			slog.String("header", header),
+			slog.Any("error", err))
+	}
+	return c.negotiate(tags)
+}
+
+// NegotiatePreferences picks the cached locale that best matches prefs, ordered
+// from most to least preferred. Both en_US and en-US spellings are accepted.
+func (c *Client) NegotiatePreferences(prefs []string) cldr.Language {
+	tags := make([]language.Tag, 0, len(prefs))
+	for _, pref := range prefs {
+		tag, err := localeTag(pref)
+		if err != nil {
+			c.logLookup(slog.LevelDebug, "Skipping invalid language preference",
+				slog.String("lang", pref),
+				slog.Any("error", err))
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return c.negotiate(tags)
+}
+
+func (c *Client) negotiate(prefs []language.Tag) cldr.Language {
+	fallback := c.defaultLocale
+	if fallback == "" {
+		fallback = cldr.LanguageEnUS
+	}
+	if len(prefs) == 0 {
+		return fallback
+	}
+
+	available := c.catalog().locales()
+	if c.shared != nil {
+		// A namespace serves the locales of the shared namespace as well.
+		for _, l := range c.shared.catalog().locales() {
+			if c.catalog().entries[l] == nil {
+				available = append(available, l)
+			}
+		}
+	}
+	sort.Slice(available, func(i, j int) bool { return available[i] < available[j] })
+
+	var (
+		locales  []cldr.Language
+		tags     []language.Tag
+		pseudoAt = -1
+	)
+	for _, l := range available {
+		tag, err := localeTag(string(l))
+		if err != nil {
+			continue
+		}
+		// The pseudo locale is only served to those asking for it by name,
+		// never as the closest match of a real locale.
+		if l == c.cache.pseudo {
+			pseudoAt = preference(prefs, tag)
+			continue
+		}
+		// The matcher uses the first tag as its default, so the default locale
+		// goes first when it is cached.
+		if l == c.defaultLocale {
+			locales = append([]cldr.Language{l}, locales...)
+			tags = append([]language.Tag{tag}, tags...)
+			continue
+		}
+		locales = append(locales, l)
+		tags = append(tags, tag)
+	}
+	if pseudoAt >= 0 {
+		// Preferences before the pseudo locale still win.
+		if l, ok := match(locales, tags, prefs[:pseudoAt]); ok {
+			return l
+		}
+		return c.cache.pseudo
+	}
+	if l, ok := match(locales, tags, prefs); ok {
+		return l
+	}
+	return fallback
+}
+
+// match returns the locale of tags that best matches prefs.
+func match(locales []cldr.Language, tags, prefs []language.Tag) (cldr.Language, bool) {
+	if len(tags) == 0 || len(prefs) == 0 {
+		return "", false
+	}
+	_, index, confidence := language.NewMatcher(tags).Match(prefs...)
+	if confidence == language.No {
+		return "", false
+	}
+	return locales[index], true
+}
+
+// preference returns the index of tag in prefs, or -1.
+func preference(prefs []language.Tag, tag language.Tag) int {
+	for i, pref := range prefs {
+		if pref == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+// localeTag parses a locale code written either as en_US or en-US.
+func localeTag(code string) (language.Tag, error) {
+	return language.Parse(strings.ReplaceAll(code, "_", "-"))
+}
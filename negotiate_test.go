@@ -0,0 +1,41 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestClient_Negotiate(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": "hello = Hello\n",
+		"uk_UA": "hello = Привіт\n",
+		"es-CO": "hello = Hola\n",
+	})
+	defer c.Close(context.Background())
+
+	tests := []struct {
+		header string
+		want   cldr.Language
+	}{
+		{"uk", cldr.LanguageUkUa},
+		{"uk-UA,uk;q=0.9,en;q=0.8", cldr.LanguageUkUa},
+		{"en-GB,en;q=0.9", cldr.LanguageEnUS},
+		{"es", "es-CO"},
+		{"fr-FR", cldr.LanguageEnUS},
+		{"", cldr.LanguageEnUS},
+		{"not a header;;", cldr.LanguageEnUS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := c.Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+
+	if got := c.NegotiatePreferences([]string{"fr", "uk_UA", "en-US"}); got != cldr.LanguageUkUa {
+		t.Errorf("NegotiatePreferences() = %q, want %q", got, cldr.LanguageUkUa)
+	}
+}
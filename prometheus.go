@@ -0,0 +1,253 @@
+package word
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// defaultBuckets are the upper bounds, in seconds, of the duration histograms.
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is a Metrics that keeps counters and histograms in memory
+// and serves them in the Prometheus text exposition format, without depending
+// on the Prometheus client library. Mount it as an http.Handler, e.g. on /metrics.
+type PrometheusMetrics struct {
+	namespace string
+
+	// Counted on every lookup, so kept apart from the metrics behind mu.
+	lookups      localeCounters
+	misses       localeCounters
+	resolverErrs localeCounters
+
+	mu            sync.Mutex
+	fallbacks     map[string]float64
+	fetchErrors   map[string]float64
+	syncs         map[string]float64
+	bundleSizes   map[string]float64
+	fetchDuration map[string]*histogram
+	syncDuration  map[string]*histogram
+}
+
+// localeCounters counts events per locale. A locale's counter is looked up
+// without locking or formatting its labels once it exists.
+type localeCounters struct {
+	m sync.Map // cldr.Language -> *atomic.Uint64
+}
+
+func (c *localeCounters) inc(lang cldr.Language) {
+	v, ok := c.m.Load(lang)
+	if !ok {
+		v, _ = c.m.LoadOrStore(lang, new(atomic.Uint64))
+	}
+	v.(*atomic.Uint64).Add(1)
+}
+
+// values returns the counters by their labels.
+func (c *localeCounters) values() map[string]float64 {
+	values := make(map[string]float64)
+	c.m.Range(func(lang, v any) bool {
+		values[labels("locale", string(lang.(cldr.Language)))] = float64(v.(*atomic.Uint64).Load())
+		return true
+	})
+	return values
+}
+
+// NewPrometheusMetrics returns a PrometheusMetrics whose metric names start
+// with namespace, "wordsdk" if empty.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	if namespace == "" {
+		namespace = "wordsdk"
+	}
+	return &PrometheusMetrics{
+		namespace:     namespace,
+		fallbacks:     make(map[string]float64),
+		fetchErrors:   make(map[string]float64),
+		syncs:         make(map[string]float64),
+		bundleSizes:   make(map[string]float64),
+		fetchDuration: make(map[string]*histogram),
+		syncDuration:  make(map[string]*histogram),
+	}
+}
+
+type histogram struct {
+	counts []uint64 // per bucket of defaultBuckets, not cumulative
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range defaultBuckets {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// labels renders label pairs as {name="value",...}.
+func labels(pairs ...string) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(pairs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabel(pairs[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func (p *PrometheusMetrics) add(m map[string]float64, key string) {
+	p.mu.Lock()
+	m[key]++
+	p.mu.Unlock()
+}
+
+func (p *PrometheusMetrics) observe(m map[string]*histogram, key string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := m[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(defaultBuckets))}
+		m[key] = h
+	}
+	h.observe(d.Seconds())
+}
+
+func (p *PrometheusMetrics) Lookup(lang cldr.Language) {
+	p.lookups.inc(lang)
+}
+
+func (p *PrometheusMetrics) Miss(lang cldr.Language) {
+	p.misses.inc(lang)
+}
+
+func (p *PrometheusMetrics) Fallback(requested, served cldr.Language) {
+	p.add(p.fallbacks, labels("requested", string(requested), "served", string(served)))
+}
+
+func (p *PrometheusMetrics) DynamicFetch(lang cldr.Language, duration time.Duration, err error) {
+	key := labels("locale", string(lang))
+	p.observe(p.fetchDuration, key, duration)
+	if err != nil {
+		p.add(p.fetchErrors, key)
+	}
+}
+
+func (p *PrometheusMetrics) Sync(duration time.Duration, outcome SyncOutcome) {
+	key := labels("outcome", string(outcome))
+	p.observe(p.syncDuration, key, duration)
+	p.add(p.syncs, key)
+}
+
+func (p *PrometheusMetrics) BundleSize(lang cldr.Language, bytes int) {
+	p.mu.Lock()
+	p.bundleSizes[labels("locale", string(lang))] = float64(bytes)
+	p.mu.Unlock()
+}
+
+func (p *PrometheusMetrics) ResolverError(lang cldr.Language) {
+	p.resolverErrs.inc(lang)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+// They are rendered before anything is written, so a slow w doesn't hold up
+// the metrics of lookups and syncs.
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	p.mu.Lock()
+	p.writeValues(&buf, "lookups_total", "counter", "Translation lookups per requested locale.", p.lookups.values())
+	p.writeValues(&buf, "misses_total", "counter", "Lookups that found no message in the locale or its fallbacks.", p.misses.values())
+	p.writeValues(&buf, "fallbacks_total", "counter", "Messages served from a fallback locale.", p.fallbacks)
+	p.writeHistograms(&buf, "dynamic_fetch_duration_seconds", "Duration of dynamic value requests to the source.", p.fetchDuration)
+	p.writeValues(&buf, "dynamic_fetch_errors_total", "counter", "Failed dynamic value requests to the source.", p.fetchErrors)
+	p.writeHistograms(&buf, "sync_duration_seconds", "Duration of static catalog syncs.", p.syncDuration)
+	p.writeValues(&buf, "syncs_total", "counter", "Static catalog syncs by outcome.", p.syncs)
+	p.writeValues(&buf, "bundle_size_bytes", "gauge", "Estimated memory of the loaded bundle per locale.", p.bundleSizes)
+	p.writeValues(&buf, "resolver_errors_total", "counter", "Messages formatted with resolver errors.", p.resolverErrs.values())
+	p.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+func (p *PrometheusMetrics) writeValues(w io.Writer, name, kind, help string, values map[string]float64) {
+	if len(values) == 0 {
+		return
+	}
+	name = p.namespace + "_" + name
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s%s %s\n", name, key, formatFloat(values[key]))
+	}
+}
+
+func (p *PrometheusMetrics) writeHistograms(w io.Writer, name, help string, histograms map[string]*histogram) {
+	if len(histograms) == 0 {
+		return
+	}
+	name = p.namespace + "_" + name
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, key := range sortedKeys(histograms) {
+		h := histograms[key]
+		// Insert le after the other labels: {a="b"} -> {a="b",le="..."}.
+		prefix := strings.TrimSuffix(key, "}")
+		if prefix != "{" {
+			prefix += ","
+		}
+
+		var cumulative uint64
+		for i, bound := range defaultBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket%sle=\"%s\"} %d\n", name, prefix, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, key, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, key, h.count)
+	}
+}
+
+// ServeHTTP serves the metrics to a Prometheus scraper.
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = p.WriteTo(w)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,36 @@
+package word
+
+import (
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// pseudoSource returns the locale Config.PseudoLocale is derived from.
+func pseudoSource(config *Config) cldr.Language {
+	switch {
+	case config.PseudoSource != "":
+		return config.PseudoSource
+	case config.DefaultLocale != "":
+		return config.DefaultLocale
+	default:
+		return cldr.LanguageEnUS
+	}
+}
+
+// isPseudo reports whether bundle is the bundle of Config.PseudoLocale.
+// Its text is pseudo-localized by the bundle itself, the client brackets the
+// formatted messages.
+func (c *Client) isPseudo(bundle *fluent.Bundle) bool {
+	return c.cache.pseudo != "" && bundle.PrimaryLocale() == c.cache.pseudo
+}
+
+// pseudoFormattedMessage brackets the value and attributes of message.
+func pseudoFormattedMessage(message *fluent.FormattedMessage) {
+	if message.Value != nil {
+		value := fluent.PseudoMessage(*message.Value)
+		message.Value = &value
+	}
+	for name, attr := range message.Attributes {
+		message.Attributes[name] = fluent.PseudoMessage(attr)
+	}
+}
@@ -0,0 +1,81 @@
+package word
+
+import (
+	"context"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestClient_PseudoLocale(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{
+		DefaultLocale: cldr.LanguageEnUS,
+		PseudoLocale:  "en_XA",
+	}, map[string]string{
+		"en_US": "hello = Hello world\ngreeting = Hi, { $name }!\nemail = Email\n    .placeholder = Your email\n",
+		"uk_UA": "hello = Привіт\n",
+	})
+	defer c.Close(context.Background())
+
+	tests := []struct {
+		name, key string
+		args      any
+		want      string
+	}{
+		{"text", "hello", nil, "[Ĥéļļö ŵöŕļð !!!]"},
+		{"placeables are kept", "greeting", map[string]any{"name": "Ann"}, "[Ĥî, Ann! !!]"},
+		{"missing message", "unknown", nil, "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.TA("en_XA", tt.key, tt.args); got != tt.want {
+				t.Errorf("TA() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := c.TAttr("en_XA", "email", "placeholder", nil), "[Ýöûŕ éɱáîļ !!!]"; got != want {
+		t.Errorf("TAttr() = %q, want %q", got, want)
+	}
+	if got, want := c.T("en_US", "hello"), "Hello world"; got != want {
+		t.Errorf("T(en_US) = %q, want %q", got, want)
+	}
+
+	l, err := c.Localizer("en_XA")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+	if got, want := l.T("hello"), "[Ĥéļļö ŵöŕļð !!!]"; got != want {
+		t.Errorf("Localizer.T() = %q, want %q", got, want)
+	}
+}
+
+func TestClient_NegotiateSkipsPseudoLocale(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{
+		DefaultLocale: cldr.LanguageEnUS,
+		PseudoLocale:  "en_XA",
+	}, map[string]string{
+		"en_US": "hello = Hello\n",
+		"uk_UA": "hello = Привіт\n",
+	})
+	defer c.Close(context.Background())
+
+	tests := []struct {
+		header string
+		want   cldr.Language
+	}{
+		{"en-GB", cldr.LanguageEnUS},
+		{"en-AU,en;q=0.9", cldr.LanguageEnUS},
+		{"fr", cldr.LanguageEnUS},
+		{"en-XA", "en_XA"},
+		{"fr,en-XA;q=0.5", "en_XA"},
+		{"uk,en-XA;q=0.5", cldr.LanguageUkUa},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := c.Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,21 @@
 package word
 
 import (
+	"context"
+	"net/http"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
 	"github.com/summit-fi/wordsdk-go/source"
 )
 
 type SDK interface {
 	T(lang string, key string) string
 	TA(lang string, key string, args any) string
+	TM(lang string, key string, args any) *fluent.FormattedMessage
+	TAttr(lang string, key string, attr string, args any) string
+	TE(lang string, key string) (string, error)
+	TAE(lang string, key string, args any) (string, error)
 	EnableDynamicContent(DynamicXKey string) *DynamicContent
 	Dynamic() *DynamicContent
 	SaveTranslations(data []source.Object) error
@@ -14,4 +23,28 @@ type SDK interface {
 	SetLogger(logger Logger)
 	Flush() error
 	Reset() error
+	Negotiate(header string) cldr.Language
+	NegotiatePreferences(prefs []string) cldr.Language
+	Localizer(lang string) (*Localizer, error)
+	Namespace(name string) SDK
+	OnUpdate(fn func(ev UpdateEvent))
+	CacheStats() CacheStats
+	Status() Status
+	WaitReady(ctx context.Context) error
+	HealthHandler() http.Handler
+
+	// Context variants of the methods above.
+	TContext(ctx context.Context, lang string, key string) string
+	TAContext(ctx context.Context, lang string, key string, args any) string
+	TMContext(ctx context.Context, lang string, key string, args any) *fluent.FormattedMessage
+	TAttrContext(ctx context.Context, lang string, key string, attr string, args any) string
+	TEContext(ctx context.Context, lang string, key string) (string, error)
+	TAEContext(ctx context.Context, lang string, key string, args any) (string, error)
+	SaveTranslationsContext(ctx context.Context, data []source.Object) error
+	SaveTranslationContext(ctx context.Context, lang string, key string, value string) error
+	FlushContext(ctx context.Context) error
+	ResetContext(ctx context.Context) error
+
+	// Close stops the background sync and drains pending dynamic writes.
+	Close(ctx context.Context) error
 }
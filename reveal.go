@@ -0,0 +1,217 @@
+package word
+
+import (
+	"context"
+	"strings"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// RevealMode selects how T and TA annotate their output with the key that
+// produced it, so in-context translation tools can map text on screen back to
+// keys. Enable it per request with WithReveal or Localizer.Reveal.
+type RevealMode int
+
+const (
+	// RevealOff returns messages unchanged.
+	RevealOff RevealMode = iota
+	// RevealAnnotate wraps messages in visible annotations, e.g.
+	// ⟦hello|en_US|static⟧Hello⟦/hello⟧.
+	RevealAnnotate
+	// RevealMarkers wraps messages in invisible markers encoding the same
+	// annotation with zero-width characters, so the layout stays intact.
+	RevealMarkers
+)
+
+// Origin is where a revealed message came from.
+type Origin string
+
+const (
+	// OriginStatic is a message of the catalog loaded from the source.
+	OriginStatic Origin = "static"
+	// OriginDynamic is a value saved through or fetched by DynamicContent.
+	OriginDynamic Origin = "dynamic"
+	// OriginMissing is a key no locale had a message for.
+	OriginMissing Origin = "missing"
+)
+
+// Revealed is a message annotated in reveal mode, as found by ParseRevealed.
+type Revealed struct {
+	Key    string
+	Locale cldr.Language
+	Origin Origin
+	Text   string
+}
+
+type revealKey struct{}
+
+// WithReveal returns a copy of ctx in which lookups made with a context use mode.
+func WithReveal(ctx context.Context, mode RevealMode) context.Context {
+	return context.WithValue(ctx, revealKey{}, mode)
+}
+
+// RevealFromContext returns the mode stored in ctx by WithReveal, RevealOff if none.
+func RevealFromContext(ctx context.Context) RevealMode {
+	mode, _ := ctx.Value(revealKey{}).(RevealMode)
+	return mode
+}
+
+// Reveal returns a copy of the Localizer that annotates its T and TA output
+// according to mode, regardless of the context of the lookup.
+func (l *Localizer) Reveal(mode RevealMode) *Localizer {
+	if l == nil {
+		return nil
+	}
+	revealed := *l
+	revealed.reveal = mode
+	return &revealed
+}
+
+// revealContext returns ctx with the Localizer's reveal mode, if it has one.
+func (l *Localizer) revealContext(ctx context.Context) context.Context {
+	if l.reveal == RevealOff {
+		return ctx
+	}
+	return WithReveal(ctx, l.reveal)
+}
+
+// reveal annotates the message key served by bundle, or missing in lang if
+// bundle is nil, according to the reveal mode of ctx.
+func (c *Client) reveal(ctx context.Context, key string, lang cldr.Language, bundle *fluent.Bundle, message string) string {
+	mode := RevealFromContext(ctx)
+	if mode == RevealOff {
+		return message
+	}
+	if bundle == nil {
+		return revealMessage(mode, Revealed{Key: key, Locale: lang, Origin: OriginMissing, Text: message})
+	}
+
+	origin := OriginStatic
+	cat := c.catalog()
+	_, saved := cat.saved[bundle.PrimaryLocale()][key]
+	if saved || bundle == cat.syncedBundle(bundle.PrimaryLocale()) {
+		origin = OriginDynamic
+	}
+	return revealMessage(mode, Revealed{Key: key, Locale: bundle.PrimaryLocale(), Origin: origin, Text: message})
+}
+
+// Delimiters of annotations. In RevealMarkers mode the header is written in
+// binary, with revealZero and revealOne.
+const (
+	annotateOpen  = "⟦"
+	annotateClose = "⟧"
+
+	markerOpen   = '\u2062' // invisible times
+	markerHeader = '\u2063' // invisible separator
+	markerClose  = '\u2064' // invisible plus
+	revealZero   = '\u200b' // zero width space
+	revealOne    = '\u200c' // zero width non-joiner
+)
+
+func revealMessage(mode RevealMode, r Revealed) string {
+	header := r.Key + "|" + string(r.Locale) + "|" + string(r.Origin)
+	switch mode {
+	case RevealAnnotate:
+		return annotateOpen + header + annotateClose + r.Text + annotateOpen + "/" + r.Key + annotateClose
+	case RevealMarkers:
+		var sb strings.Builder
+		sb.WriteRune(markerOpen)
+		for i := 0; i < len(header); i++ {
+			for bit := 7; bit >= 0; bit-- {
+				if header[i]&(1<<bit) != 0 {
+					sb.WriteRune(revealOne)
+				} else {
+					sb.WriteRune(revealZero)
+				}
+			}
+		}
+		sb.WriteRune(markerHeader)
+		sb.WriteString(r.Text)
+		sb.WriteRune(markerClose)
+		return sb.String()
+	default:
+		return r.Text
+	}
+}
+
+// ParseRevealed returns the messages annotated in text, e.g. a rendered page,
+// in either reveal mode, in the order they appear.
+func ParseRevealed(text string) []Revealed {
+	var found []Revealed
+	for len(text) > 0 {
+		annotated := strings.Index(text, annotateOpen)
+		marked := strings.IndexRune(text, markerOpen)
+		var (
+			r    Revealed
+			rest string
+			ok   bool
+		)
+		switch {
+		case annotated < 0 && marked < 0:
+			return found
+		case marked < 0 || annotated >= 0 && annotated < marked:
+			r, rest, ok = parseAnnotation(text[annotated+len(annotateOpen):])
+		default:
+			r, rest, ok = parseMarkers(text[marked+len(string(markerOpen)):])
+		}
+		if ok {
+			found = append(found, r)
+		}
+		text = rest
+	}
+	return found
+}
+
+// parseAnnotation parses text after annotateOpen.
+func parseAnnotation(text string) (Revealed, string, bool) {
+	header, rest, ok := strings.Cut(text, annotateClose)
+	if !ok {
+		return Revealed{}, "", false
+	}
+	r, ok := parseHeader(header)
+	if !ok {
+		return Revealed{}, rest, false
+	}
+	r.Text, rest, ok = strings.Cut(rest, annotateOpen+"/"+r.Key+annotateClose)
+	return r, rest, ok
+}
+
+// parseMarkers parses text after markerOpen.
+func parseMarkers(text string) (Revealed, string, bool) {
+	bits, rest, ok := strings.Cut(text, string(markerHeader))
+	if !ok {
+		return Revealed{}, "", false
+	}
+	var header []byte
+	var b byte
+	n := 0
+	for _, bit := range bits {
+		switch bit {
+		case revealZero:
+			b <<= 1
+		case revealOne:
+			b = b<<1 | 1
+		default:
+			return Revealed{}, rest, false
+		}
+		if n++; n%8 == 0 {
+			header = append(header, b)
+			b = 0
+		}
+	}
+	r, ok := parseHeader(string(header))
+	if !ok {
+		return Revealed{}, rest, false
+	}
+	r.Text, rest, ok = strings.Cut(rest, string(markerClose))
+	return r, rest, ok
+}
+
+func parseHeader(header string) (Revealed, bool) {
+	parts := strings.Split(header, "|")
+	if len(parts) != 3 {
+		return Revealed{}, false
+	}
+	return Revealed{Key: parts[0], Locale: cldr.Language(parts[1]), Origin: Origin(parts[2])}, true
+}
@@ -0,0 +1,104 @@
+package word
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_Reveal(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{
+		DefaultLocale: cldr.LanguageEnUS,
+		Fallbacks:     map[cldr.Language][]cldr.Language{"uk_UA": {"en_US"}},
+	}, map[string]string{
+		"en_US": "hello = Hello\ngreeting = Hi, { $name }!\n",
+		"uk_UA": "greeting = Привіт, { $name }!\n",
+	})
+	defer c.Close(context.Background())
+
+	ctx := WithReveal(context.Background(), RevealAnnotate)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"off", c.TContext(context.Background(), "uk_UA", "hello"), "Hello"},
+		{"fallback", c.TContext(ctx, "uk_UA", "hello"), "⟦hello|en_US|static⟧Hello⟦/hello⟧"},
+		{"args", c.TAContext(ctx, "uk_UA", "greeting", map[string]any{"name": "Ann"}), "⟦greeting|uk_UA|static⟧Привіт, Ann!⟦/greeting⟧"},
+		{"missing", c.TContext(ctx, "uk_UA", "unknown"), "⟦unknown|uk_UA|missing⟧unknown⟦/unknown⟧"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+
+	l, err := c.Localizer("uk_UA")
+	if err != nil {
+		t.Fatalf("Localizer() error = %v", err)
+	}
+	page := l.Reveal(RevealMarkers).T("hello") + " / " + l.TContext(ctx, "greeting")
+	if strings.Contains(page, "⟦hello") {
+		t.Errorf("markers are visible: %q", page)
+	}
+	want := []Revealed{
+		{Key: "hello", Locale: "en_US", Origin: OriginStatic, Text: "Hello"},
+		{Key: "greeting", Locale: "uk_UA", Origin: OriginStatic, Text: "Привіт, {$name}!"},
+	}
+	if got := ParseRevealed(page); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRevealed() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDynamicContent_RevealOrigin(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+	d := c.EnableDynamicContent("key")
+	if err := d.SaveTranslations([]source.Object{{LocaleCode: "en_US", Key: "product", Value: "Chair"}}); err != nil {
+		t.Fatalf("SaveTranslations() error = %v", err)
+	}
+
+	ctx := WithReveal(context.Background(), RevealAnnotate)
+	if got, want := d.TContext(ctx, "en_US", "product"), "⟦product|en_US|dynamic⟧Chair⟦/product⟧"; got != want {
+		t.Errorf("TContext() = %q, want %q", got, want)
+	}
+}
+
+func TestMiddleware_Reveal(t *testing.T) {
+	c, _ := tempFtlClient(t, Config{DefaultLocale: cldr.LanguageEnUS}, map[string]string{
+		"en_US": "hello = Hello\n",
+	})
+	defer c.Close(context.Background())
+
+	handler := Middleware(c, MiddlewareOptions{
+		Reveal: func(r *http.Request) RevealMode {
+			if r.URL.Query().Get("reveal") != "" {
+				return RevealAnnotate
+			}
+			return RevealOff
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).T("hello")))
+	}))
+
+	for target, want := range map[string]string{
+		"/":          "Hello",
+		"/?reveal=1": "⟦hello|en_US|static⟧Hello⟦/hello⟧",
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if got := rec.Body.String(); got != want {
+			t.Errorf("GET %s = %q, want %q", target, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package word
+
+import (
+	"strings"
+
+	"github.com/summit-fi/wordsdk-go/fluent"
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// revisionKey identifies a value of a namespace.
+type revisionKey struct {
+	namespace string
+	lang      cldr.Language
+	key       string
+}
+
+// revisions holds the revisions of the values loaded from the source, of every
+// namespace; see source.Object.Revision. Values without one are left out.
+type revisions map[revisionKey]string
+
+func newRevisions(data []source.Object) revisions {
+	var result revisions
+	for _, item := range data {
+		if item.Revision == "" {
+			continue
+		}
+		if result == nil {
+			result = make(revisions)
+		}
+		result[revisionKey{item.Namespace, cldr.Language(item.LocaleCode), strings.TrimSpace(item.Key)}] = item.Revision
+	}
+	return result
+}
+
+// merge returns the revisions of r overridden by those of other.
+func (r revisions) merge(other revisions) revisions {
+	result := make(revisions, len(r)+len(other))
+	for k, revision := range r {
+		result[k] = revision
+	}
+	for k, revision := range other {
+		result[k] = revision
+	}
+	return result
+}
+
+// revision returns the revision of the value of key that bundle was built
+// from, or "" if it has none. A value saved through DynamicContent has the
+// revision of the loaded value only while they are the same. Revisions are
+// kept in the catalog of the shared namespace, for every namespace.
+func (c *Client) revision(bundle *fluent.Bundle, key string) string {
+	lang := bundle.PrimaryLocale()
+	root := c.root().catalog()
+	for owner := c; owner != nil; owner = owner.shared {
+		cat := owner.catalog()
+		k := revisionKey{owner.namespace, lang, key}
+		if cat.syncedBundle(lang) == bundle {
+			return root.dynamicRevisions[k]
+		}
+		entry := cat.entries[lang]
+		if entry == nil || entry.bundle.Load() != bundle {
+			continue
+		}
+		if saved, ok := cat.saved[lang][key]; ok {
+			if static, ok := cat.static[lang][key]; !ok || static != saved {
+				return ""
+			}
+		}
+		return root.revisions[k]
+	}
+	return ""
+}
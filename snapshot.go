@@ -0,0 +1,114 @@
+package word
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+	"github.com/summit-fi/wordsdk-go/utils/safefile"
+)
+
+// snapshot is the on-disk copy of the static catalog written to
+// Config.SnapshotPath, used to start without reaching the source.
+type snapshot struct {
+	Checksum string          `json:"checksum"`
+	SavedAt  time.Time       `json:"savedAt"`
+	Objects  []source.Object `json:"objects"`
+}
+
+func readSnapshot(path string) (*snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// loadSnapshot publishes the catalog saved at the snapshot path, if one is
+// configured and readable, and reports whether it did.
+func (c *Client) loadSnapshot() bool {
+	if c.snapshotPath == "" {
+		return false
+	}
+
+	snap, err := readSnapshot(c.snapshotPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			c.log(slog.LevelError, "Failed to read snapshot", slog.String("path", c.snapshotPath), slog.Any("error", err))
+		}
+		return false
+	}
+
+	shared, namespaced := splitNamespaces(snap.Objects)
+	cat, err := newCatalog(c.cache, nil, newCatalogValues(shared), nil, nil, nil)
+	if err != nil {
+		c.log(slog.LevelError, "Failed to load snapshot", slog.String("path", c.snapshotPath), slog.Any("error", err))
+		return false
+	}
+
+	c.current.Store(cat)
+	c.cache.enforce()
+	c.setNamespaces(namespaced, false)
+	c.checksum = snap.Checksum
+	c.status.snapshot(snap.Checksum)
+	c.log(slog.LevelInfo, "Loaded snapshot",
+		slog.String("path", c.snapshotPath),
+		slog.Time("saved_at", snap.SavedAt),
+		slog.String("checksum", snap.Checksum))
+	return true
+}
+
+// saveSnapshot writes the static values, those of the namespaces included, and
+// their checksum to the snapshot path, if one is configured. Failures are
+// logged, as the catalog in memory is still valid.
+func (c *Client) saveSnapshot(checksum string, static catalogValues) {
+	if c.snapshotPath == "" {
+		return
+	}
+
+	snap := snapshot{
+		Checksum: checksum,
+		SavedAt:  time.Now().UTC(),
+		Objects:  append(static.objects(), c.namespaceObjects()...),
+	}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		c.log(slog.LevelError, "Failed to encode snapshot", slog.Any("error", err))
+		return
+	}
+	if err := safefile.Write(c.snapshotPath, b, 0644); err != nil {
+		c.log(slog.LevelError, "Failed to write snapshot", slog.String("path", c.snapshotPath), slog.Any("error", err))
+		return
+	}
+	c.log(slog.LevelDebug, "Snapshot written", slog.String("path", c.snapshotPath), slog.String("checksum", checksum))
+}
+
+// objects returns the values of v as source objects, sorted by locale and key.
+func (v catalogValues) objects() []source.Object {
+	var objects []source.Object
+	for l, values := range v {
+		for key, value := range values {
+			objects = append(objects, source.Object{
+				LocaleCode: string(l),
+				Key:        key,
+				Value:      value,
+			})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].LocaleCode != objects[j].LocaleCode {
+			return objects[i].LocaleCode < objects[j].LocaleCode
+		}
+		return objects[i].Key < objects[j].Key
+	})
+	return objects
+}
@@ -0,0 +1,96 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// stubSource serves static objects from memory and counts the checksums it
+// was asked to revalidate.
+type stubSource struct {
+	mu        sync.Mutex
+	objects   []source.Object
+	checksum  string
+	err       error
+	checksums []string
+}
+
+func (s *stubSource) LoadAllStatic(checksumIn string) ([]source.Object, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checksums = append(s.checksums, checksumIn)
+	if s.err != nil {
+		return nil, "", s.err
+	}
+	if checksumIn == s.checksum {
+		return nil, checksumIn, nil
+	}
+	return s.objects, s.checksum, nil
+}
+
+func (s *stubSource) LoadAllDynamic(dynamicKey string, checksumIn string) ([]source.Object, string, error) {
+	return s.LoadAllStatic(checksumIn)
+}
+
+func (s *stubSource) LoadOneDynamic(accessKey, lang, key string) (string, error) {
+	return "", nil
+}
+
+func (s *stubSource) SaveDynamic(accessKey string, data []source.Object) error {
+	return nil
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	online := &stubSource{
+		objects:  []source.Object{{LocaleCode: "en_US", Key: "hello", Value: "Hello"}},
+		checksum: "v1",
+	}
+	sdk, err := NewClient(&Config{Source: online, SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	sdk.Close(context.Background())
+
+	// The source is down, but the snapshot lets the client start.
+	offline := &stubSource{err: errors.New("word api is down")}
+	if _, err := NewClient(&Config{Source: offline}); err == nil {
+		t.Fatal("NewClient() without snapshot succeeded with a failing source")
+	}
+
+	sdk, err = NewClient(&Config{Source: offline, SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("NewClient() with snapshot error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+
+	if got := sdk.T("en_US", "hello"); got != "Hello" {
+		t.Errorf("T() = %q, want %q", got, "Hello")
+	}
+
+	// The background revalidation sends the stored checksum.
+	deadline := time.Now().Add(time.Second)
+	for {
+		offline.mu.Lock()
+		checksums := append([]string{}, offline.checksums...)
+		offline.mu.Unlock()
+
+		if len(checksums) == 2 {
+			if checksums[1] != "v1" {
+				t.Errorf("revalidated with checksum %q, want the snapshot checksum v1", checksums[1])
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no revalidation after startup, checksums sent: %q", checksums)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -3,6 +3,7 @@ package source
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -43,39 +44,49 @@ func normalizeFTLNewlines(value string) string {
 	return strings.ReplaceAll(value, "\r", "\n")
 }
 
+// SaveDynamic appends data to the files of its namespaces and locales. Objects
+// with a Revision are written only if it is still the checksum of the value in
+// the files; otherwise nothing is written and a *ConflictError is returned.
 func (f *Ftl) SaveDynamic(accessKey string, data []Object) error {
 	f.Lock()
 	defer f.Unlock()
 
-	var dataMap = make(map[string]map[string]string) // localeCode -> key -> value
+	var dataMap = make(map[file]map[string]string) // namespace, localeCode -> key -> value
 	for _, datum := range data {
-		if _, ok := dataMap[datum.LocaleCode]; !ok {
-			dataMap[datum.LocaleCode] = make(map[string]string)
+		fi := file{namespace: datum.Namespace, localeCode: datum.LocaleCode}
+		if _, ok := dataMap[fi]; !ok {
+			dataMap[fi] = make(map[string]string)
 		}
-		dataMap[datum.LocaleCode][datum.Key] = datum.Value
+		dataMap[fi][datum.Key] = datum.Value
 	}
 
+	contents := make(map[string][]byte)
 	for _, fi := range f.files {
-		if _, ok := dataMap[fi.localeCode]; !ok {
+		if _, ok := dataMap[file{namespace: fi.namespace, localeCode: fi.localeCode}]; !ok {
 			// File with this locale is not loaded/attached
 			continue
 		}
-
-		file, err := os.Open(fi.path)
+		b, err := os.ReadFile(fi.path)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
+		contents[fi.path] = b
+	}
 
-		b, err := io.ReadAll(file)
-		if err != nil {
-			return err
+	if err := f.checkRevisions(data, contents); err != nil {
+		return err
+	}
+
+	for _, fi := range f.files {
+		values, ok := dataMap[file{namespace: fi.namespace, localeCode: fi.localeCode}]
+		if !ok {
+			continue
 		}
 
 		var builder strings.Builder
-		builder.Write(b)
+		builder.Write(contents[fi.path])
 
-		for key, value := range dataMap[fi.localeCode] {
+		for key, value := range values {
 			builder.WriteString("\n")
 			builder.WriteString(FormatFTLEntry(key, value))
 		}
@@ -88,15 +99,102 @@ func (f *Ftl) SaveDynamic(accessKey string, data []Object) error {
 	return nil
 }
 
+// checkRevisions returns a *ConflictError listing the objects of data whose
+// Revision is not the revision of their current value in contents, the files
+// by path.
+func (f *Ftl) checkRevisions(data []Object, contents map[string][]byte) error {
+	current := make(map[file]map[string]string)
+	var conflicts []Conflict
+	for _, datum := range data {
+		if datum.Revision == "" {
+			continue
+		}
+
+		fi := file{namespace: datum.Namespace, localeCode: datum.LocaleCode}
+		values, ok := current[fi]
+		if !ok {
+			// Later files override earlier ones, as in LoadAllStatic.
+			values = make(map[string]string)
+			for _, attached := range f.files {
+				if attached.namespace == fi.namespace && attached.localeCode == fi.localeCode {
+					for _, obj := range FtlParse(fi.localeCode, contents[attached.path]) {
+						values[obj.Key] = obj.Value
+					}
+				}
+			}
+			current[fi] = values
+		}
+
+		var revision string
+		if value, ok := values[datum.Key]; ok {
+			revision = valueRevision(value)
+		}
+		if revision != datum.Revision {
+			conflicts = append(conflicts, Conflict{
+				Namespace:  datum.Namespace,
+				LocaleCode: datum.LocaleCode,
+				Key:        datum.Key,
+				Revision:   revision,
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// valueRevision is the revision of a value read from a file: a checksum of it.
+func valueRevision(value string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:16]
+}
+
 func (f *Ftl) LoadAllDynamic(key string, checksumIn string) (result []Object, checksumOut string, err error) {
 	return f.LoadAllStatic(checksumIn)
 }
+
+// Ftl works on local files, so the context variants only check for
+// cancellation before touching the disk.
+
+func (f *Ftl) LoadAllStaticContext(ctx context.Context, checksumIn string) ([]Object, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	return f.LoadAllStatic(checksumIn)
+}
+
+func (f *Ftl) LoadAllDynamicContext(ctx context.Context, key string, checksumIn string) ([]Object, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	return f.LoadAllDynamic(key, checksumIn)
+}
+
+func (f *Ftl) LoadOneDynamicContext(ctx context.Context, accessKey, lang, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return key, err
+	}
+	return f.LoadOneDynamic(accessKey, lang, key)
+}
+
+func (f *Ftl) SaveDynamicContext(ctx context.Context, accessKey string, data []Object) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.SaveDynamic(accessKey, data)
+}
 func (f *Ftl) LoadOneDynamic(accessKey, lang, key string) (string, error) {
+	return f.loadOne("", lang, key)
+}
+
+// loadOne reads key from the files of namespace and lang.
+func (f *Ftl) loadOne(namespace, lang, key string) (string, error) {
 	f.RLock()
 	defer f.RUnlock()
 
 	for _, file := range f.files {
-		if file.localeCode == lang {
+		if file.namespace == namespace && file.localeCode == lang {
 			f, err := os.Open(file.path)
 			if err != nil {
 				return key, err
@@ -117,10 +215,91 @@ func (f *Ftl) LoadOneDynamic(accessKey, lang, key string) (string, error) {
 		}
 	}
 
-	return key, fmt.Errorf("Key: %s, nof found", key) // Not found
+	return key, fmt.Errorf("key %s: %w", key, ErrNotFound)
+}
+
+func (f *Ftl) LoadManyDynamicContext(ctx context.Context, accessKey, lang string, keys []string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.LoadManyDynamic(accessKey, lang, keys)
+}
+
+// LoadManyDynamic reads the files of lang once for all keys.
+func (f *Ftl) LoadManyDynamic(accessKey, lang string, keys []string) (map[string]string, error) {
+	return f.loadMany("", lang, keys)
+}
+
+// loadMany reads keys from the files of namespace and lang.
+func (f *Ftl) loadMany(namespace, lang string, keys []string) (map[string]string, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	wanted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		wanted[key] = struct{}{}
+	}
+
+	result := make(map[string]string, len(keys))
+	for _, file := range f.files {
+		if file.namespace != namespace || file.localeCode != lang {
+			continue
+		}
+		b, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range FtlParse(lang, b) {
+			if _, ok := wanted[obj.Key]; !ok {
+				continue
+			}
+			// The first file holding a key wins, as in LoadOneDynamic.
+			if _, ok := result[obj.Key]; !ok {
+				result[obj.Key] = strings.Trim(obj.Value, "\n")
+			}
+		}
+	}
+	return result, nil
+}
+
+// WithNamespace returns the Ftl scoped to namespace: its dynamic lookups read
+// only the files added for namespace. It shares the files of f, including the
+// ones added later.
+func (f *Ftl) WithNamespace(namespace string) Source {
+	return &ftlNamespace{Ftl: f, namespace: namespace}
+}
+
+// ftlNamespace is an Ftl whose dynamic lookups are scoped to a namespace.
+// Loading all values and saving are not scoped: objects carry their namespace.
+type ftlNamespace struct {
+	*Ftl
+	namespace string
+}
+
+func (n *ftlNamespace) LoadOneDynamic(accessKey, lang, key string) (string, error) {
+	return n.loadOne(n.namespace, lang, key)
+}
+
+func (n *ftlNamespace) LoadOneDynamicContext(ctx context.Context, accessKey, lang, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return key, err
+	}
+	return n.LoadOneDynamic(accessKey, lang, key)
+}
+
+func (n *ftlNamespace) LoadManyDynamic(accessKey, lang string, keys []string) (map[string]string, error) {
+	return n.loadMany(n.namespace, lang, keys)
+}
+
+func (n *ftlNamespace) LoadManyDynamicContext(ctx context.Context, accessKey, lang string, keys []string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return n.LoadManyDynamic(accessKey, lang, keys)
 }
 
 type file struct {
+	namespace  string
 	localeCode string
 	path       string
 }
@@ -130,8 +309,13 @@ func NewFtl() *Ftl {
 }
 
 func (f *Ftl) AddLocaleFile(localeCode string, path string) error {
-	f.RLock()
-	defer f.RUnlock()
+	return f.AddNamespaceFile("", localeCode, path)
+}
+
+// AddNamespaceFile adds the file of a locale whose messages belong to namespace.
+func (f *Ftl) AddNamespaceFile(namespace, localeCode string, path string) error {
+	f.Lock()
+	defer f.Unlock()
 
 	// Check if file exists
 	fi, err := os.OpenFile(path, os.O_RDWR, 0755)
@@ -142,12 +326,24 @@ func (f *Ftl) AddLocaleFile(localeCode string, path string) error {
 
 	// Add to files
 	f.files = append(f.files, file{
+		namespace:  namespace,
 		localeCode: localeCode,
 		path:       path,
 	})
 	return nil
 }
 
+// AddNamespaceFiles adds the files of namespace, given as locale -> path.
+func (f *Ftl) AddNamespaceFiles(namespace string, paths map[string]string) error {
+	for locale, path := range paths {
+		err := f.AddNamespaceFile(namespace, locale, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *Ftl) AddLocaleFiles(paths map[string]string) error {
 	for locale, path := range paths {
 		err := f.AddLocaleFile(locale, path)
@@ -159,6 +355,7 @@ func (f *Ftl) AddLocaleFiles(paths map[string]string) error {
 }
 
 type openedFile struct {
+	namespace  string
 	localeCode string
 	bytes      []byte
 }
@@ -273,6 +470,7 @@ func (f *Ftl) LoadAllStatic(checksumIn string) ([]Object, string, error) {
 		}
 
 		files = append(files, openedFile{
+			namespace:  file.namespace,
 			localeCode: file.localeCode,
 			bytes:      b,
 		})
@@ -293,7 +491,12 @@ func (f *Ftl) LoadAllStatic(checksumIn string) ([]Object, string, error) {
 	// Format result
 	var objects []Object
 	for _, file := range files {
-		objects = append(objects, FtlParse(file.localeCode, file.bytes)...)
+		parsed := FtlParse(file.localeCode, file.bytes)
+		for i := range parsed {
+			parsed[i].Namespace = file.namespace
+			parsed[i].Revision = valueRevision(parsed[i].Value)
+		}
+		objects = append(objects, parsed...)
 	}
 
 	return objects, checksum, nil
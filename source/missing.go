@@ -0,0 +1,94 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/utils/safefile"
+)
+
+// MissingKey is a key that was looked up but had no translation.
+type MissingKey struct {
+	LocaleCode string    `json:"localeCode"`
+	Key        string    `json:"key"`
+	Caller     string    `json:"caller,omitempty"`
+	Count      int       `json:"count"`
+	FirstSeen  time.Time `json:"firstSeen"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+// MissingReporter is implemented by sources that accept reports of missing keys,
+// so translators get the list of keys the product actually asked for.
+type MissingReporter interface {
+	ReportMissing(ctx context.Context, keys []MissingKey) error
+}
+
+// MissingFile is a MissingReporter that keeps missing keys in a local JSON file.
+// Reports are merged with the keys already in the file.
+type MissingFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewMissingFile(path string) *MissingFile {
+	return &MissingFile{path: path}
+}
+
+func (m *MissingFile) ReportMissing(ctx context.Context, keys []MissingKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var existing []MissingKey
+	b, err := os.ReadFile(m.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &existing); err != nil {
+			return err
+		}
+	}
+
+	type id struct{ locale, key string }
+	merged := make(map[id]MissingKey, len(existing)+len(keys))
+	for _, k := range existing {
+		merged[id{k.LocaleCode, k.Key}] = k
+	}
+	for _, k := range keys {
+		prev, ok := merged[id{k.LocaleCode, k.Key}]
+		if ok {
+			k.Count += prev.Count
+			k.FirstSeen = prev.FirstSeen
+			if k.Caller == "" {
+				k.Caller = prev.Caller
+			}
+		}
+		merged[id{k.LocaleCode, k.Key}] = k
+	}
+
+	result := make([]MissingKey, 0, len(merged))
+	for _, k := range merged {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LocaleCode != result[j].LocaleCode {
+			return result[i].LocaleCode < result[j].LocaleCode
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	b, err = json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.Write(m.path, b, 0644)
+}
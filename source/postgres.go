@@ -3,17 +3,30 @@ package source
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"sync"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/summit-fi/wordsdk-go/utils/locale"
 )
 
+// ErrNamespaceUnsupported is returned by Postgres.SaveDynamic for objects of a
+// namespace: all values of a Postgres source are shared.
+var ErrNamespaceUnsupported = errors.New("namespaces are not supported")
+
 type Postgres struct {
 	ctx  context.Context
 	pool *pgxpool.Pool
+
+	// versioned records whether the translation table has the version column
+	// revisions are read from. It is looked up on first use; see hasVersion.
+	versionMu sync.Mutex
+	versioned *bool
 }
 
 func NewPostgres(ctx context.Context, connString string) (*Postgres, error) {
@@ -62,7 +75,7 @@ func (p *Postgres) LoadTranslationsFromFiles(files []string) error {
 
 		parsed := FtlParse(f.localeCode, f.bytes)
 
-		err := p.batchSaveTranslations(parsed)
+		err := p.batchSaveTranslations(p.ctx, parsed)
 		if err != nil {
 			return fmt.Errorf("failed to save translations from remote files: %v", err)
 		}
@@ -85,8 +98,12 @@ func computeChecksum(translations []Object) string {
 }
 
 func (p *Postgres) LoadAllStatic(checksumIn string) (result []Object, checksumOut string, err error) {
+	return p.LoadAllStaticContext(p.ctx, checksumIn)
+}
 
-	result, err = p.getAllKeys()
+func (p *Postgres) LoadAllStaticContext(ctx context.Context, checksumIn string) (result []Object, checksumOut string, err error) {
+
+	result, err = p.getAllKeys(ctx)
 	if err != nil {
 		return nil, "", err
 	}
@@ -103,45 +120,117 @@ func (p *Postgres) LoadAllDynamic(accessKey, checksumIn string) (result []Object
 	return p.LoadAllStatic(checksumIn)
 }
 
+func (p *Postgres) LoadAllDynamicContext(ctx context.Context, accessKey, checksumIn string) (result []Object, checkSumOut string, err error) {
+	return p.LoadAllStaticContext(ctx, checksumIn)
+}
+
 func (p *Postgres) LoadOneDynamic(accessKey, lang, key string) (string, error) {
-	value, err := p.getTranslation(lang, key)
+	return p.LoadOneDynamicContext(p.ctx, accessKey, lang, key)
+}
+
+func (p *Postgres) LoadOneDynamicContext(ctx context.Context, accessKey, lang, key string) (string, error) {
+	value, err := p.getTranslation(ctx, lang, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("key %s: %w", key, ErrNotFound)
+	}
 	if err != nil {
 		return "", err
 	}
 	return value, nil
 }
 
+func (p *Postgres) LoadManyDynamic(accessKey, lang string, keys []string) (map[string]string, error) {
+	return p.LoadManyDynamicContext(p.ctx, accessKey, lang, keys)
+}
+
+func (p *Postgres) LoadManyDynamicContext(ctx context.Context, accessKey, lang string, keys []string) (map[string]string, error) {
+	return p.getTranslations(ctx, lang, keys)
+}
+
 func (p *Postgres) SaveDynamic(accessKey string, data []Object) error {
-	var dataMap = make(map[string]map[string]interface{}) // localeCode -> key -> value
+	return p.SaveDynamicContext(p.ctx, accessKey, data)
+}
+
+// SaveDynamicContext saves data in one transaction. Objects with a Revision
+// are saved only if it is still the version of the row; otherwise nothing is
+// saved and a *ConflictError is returned. Tables without the version column
+// have no revisions, so their values are saved unconditionally.
+// The table has no namespace column, so objects of a namespace are refused
+// rather than saved over the shared values with the same key.
+func (p *Postgres) SaveDynamicContext(ctx context.Context, accessKey string, data []Object) error {
+	for _, datum := range data {
+		if datum.Namespace != "" {
+			return fmt.Errorf("key %s: namespace %q: %w", datum.Key, datum.Namespace, ErrNamespaceUnsupported)
+		}
+	}
+
+	var dataMap = make(map[string]map[string]Object) // localeCode -> key -> object
 	for _, datum := range data {
 		if _, ok := dataMap[datum.LocaleCode]; !ok {
-			dataMap[datum.LocaleCode] = make(map[string]interface{})
+			dataMap[datum.LocaleCode] = make(map[string]Object)
 		}
-		dataMap[datum.LocaleCode][datum.Key] = datum.Value
+		dataMap[datum.LocaleCode][datum.Key] = datum
 	}
 
 	var translations []Object
-	for localeCode, keyValues := range dataMap {
-		for key, value := range keyValues {
-			if strValue, ok := value.(string); ok {
-				translations = append(translations, Object{
-					LocaleCode: localeCode,
-					Key:        key,
-					Value:      strValue,
-				})
-			}
+	for _, objects := range dataMap {
+		for _, object := range objects {
+			translations = append(translations, object)
 		}
 	}
 
-	return p.batchSaveTranslations(translations)
+	return p.batchSaveTranslations(ctx, translations)
+}
+
+// AddVersionColumn adds the version column the revisions of values are read
+// from to the translation table, if it doesn't have one yet. Without it,
+// values are loaded without revisions and saved unconditionally.
+func (p *Postgres) AddVersionColumn(ctx context.Context) error {
+	_, err := p.pool.Exec(ctx, "ALTER TABLE translation ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1")
+	if err != nil {
+		return err
+	}
+	p.versionMu.Lock()
+	defer p.versionMu.Unlock()
+	versioned := true
+	p.versioned = &versioned
+	return nil
+}
+
+// hasVersion reports whether the translation table has the version column.
+// The answer is looked up once; tables created before revisions were
+// introduced don't have it.
+func (p *Postgres) hasVersion(ctx context.Context) (bool, error) {
+	p.versionMu.Lock()
+	defer p.versionMu.Unlock()
+	if p.versioned != nil {
+		return *p.versioned, nil
+	}
+
+	var versioned bool
+	err := p.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = to_regclass('translation') AND attname = 'version' AND NOT attisdropped)").Scan(&versioned)
+	if err != nil {
+		return false, err
+	}
+	p.versioned = &versioned
+	return versioned, nil
 }
 
 func (p *Postgres) Close() {
 	p.pool.Close()
 }
 
-func (p *Postgres) getAllKeys() ([]Object, error) {
-	rows, err := p.pool.Query(p.ctx, "SELECT lang, code, value FROM translation;")
+func (p *Postgres) getAllKeys(ctx context.Context) ([]Object, error) {
+	versioned, err := p.hasVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT lang, code, value, 0::bigint FROM translation;"
+	if versioned {
+		query = "SELECT lang, code, value, version FROM translation;"
+	}
+
+	rows, err := p.pool.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -150,29 +239,56 @@ func (p *Postgres) getAllKeys() ([]Object, error) {
 	var datum []Object
 	for rows.Next() {
 		var obj Object
+		var version int64
 		if err := rows.Scan(
 			&obj.LocaleCode,
 			&obj.Key,
-			&obj.Value); err != nil {
+			&obj.Value,
+			&version); err != nil {
 			return nil, err
 		}
+		if versioned {
+			obj.Revision = strconv.FormatInt(version, 10)
+		}
 		datum = append(datum, obj)
 	}
 	return datum, nil
 }
 
-func (p *Postgres) getTranslation(lang, key string) (string, error) {
+func (p *Postgres) getTranslation(ctx context.Context, lang, key string) (string, error) {
 	var value string
-	err := p.pool.QueryRow(p.ctx, "SELECT value FROM translation WHERE lang = $1 AND code = $2", lang, key).Scan(&value)
+	err := p.pool.QueryRow(ctx, "SELECT value FROM translation WHERE lang = $1 AND code = $2", lang, key).Scan(&value)
 	if err != nil {
 		return "", err
 	}
 	return value, nil
 }
 
+func (p *Postgres) getTranslations(ctx context.Context, lang string, keys []string) (map[string]string, error) {
+	rows, err := p.pool.Query(ctx, "SELECT code, value FROM translation WHERE lang = $1 AND code = ANY($2)", lang, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string, len(keys))
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, rows.Err()
+}
+
 func (p *Postgres) saveTranslation(ctx context.Context, lang, key, value string) error {
+	versioned, err := p.hasVersion(ctx)
+	if err != nil {
+		return err
+	}
 	keyType := "content"
-	_, err := p.pool.Exec(ctx, "INSERT INTO translation (type, lang, code, value) VALUES ($1, $2, $3, $4) ON CONFLICT (lang, code) DO UPDATE SET value = EXCLUDED.value",
+	_, err = p.pool.Exec(ctx, upsertStatement(versioned),
 		keyType,
 		lang,
 		key,
@@ -181,16 +297,63 @@ func (p *Postgres) saveTranslation(ctx context.Context, lang, key, value string)
 	return err
 }
 
-func (p *Postgres) batchSaveTranslations(translations []Object) error {
-	tx, err := p.pool.Begin(p.ctx)
+// upsertStatement returns the statement saving a value; with versioned set, it
+// bumps the version of the row it replaces.
+func upsertStatement(versioned bool) string {
+	if versioned {
+		return "INSERT INTO translation (type, lang, code, value) VALUES ($1, $2, $3, $4) ON CONFLICT (lang, code) DO UPDATE SET value = EXCLUDED.value, version = translation.version + 1"
+	}
+	return "INSERT INTO translation (type, lang, code, value) VALUES ($1, $2, $3, $4) ON CONFLICT (lang, code) DO UPDATE SET value = EXCLUDED.value"
+}
+
+// lockRevision returns the version of the row of lang and key, "" if there is
+// none, and locks the row until tx ends.
+func lockRevision(ctx context.Context, tx pgx.Tx, lang, key string) (string, error) {
+	var version int64
+	err := tx.QueryRow(ctx, "SELECT version FROM translation WHERE lang = $1 AND code = $2 FOR UPDATE", lang, key).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(version, 10), nil
+}
+
+// batchSaveTranslations saves translations in one transaction. Without the
+// version column, revisions can't be checked and every value is saved.
+func (p *Postgres) batchSaveTranslations(ctx context.Context, translations []Object) error {
+	versioned, err := p.hasVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(p.ctx)
+	defer tx.Rollback(ctx)
 
 	keyType := "content"
+	var conflicts []Conflict
 	for _, t := range translations {
-		_, err := tx.Exec(p.ctx, "INSERT INTO translation (type, lang, code, value) VALUES ($1, $2, $3, $4) ON CONFLICT (lang, code) DO UPDATE SET value = EXCLUDED.value",
+		if versioned && t.Revision != "" {
+			revision, err := lockRevision(ctx, tx, t.LocaleCode, t.Key)
+			if err != nil {
+				return err
+			}
+			if revision != t.Revision {
+				conflicts = append(conflicts, Conflict{
+					Namespace:  t.Namespace,
+					LocaleCode: t.LocaleCode,
+					Key:        t.Key,
+					Revision:   revision,
+				})
+				continue
+			}
+		}
+
+		_, err := tx.Exec(ctx, upsertStatement(versioned),
 			keyType,
 			t.LocaleCode,
 			t.Key,
@@ -201,5 +364,8 @@ func (p *Postgres) batchSaveTranslations(translations []Object) error {
 		}
 	}
 
-	return tx.Commit(p.ctx)
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return tx.Commit(ctx)
 }
@@ -2,17 +2,22 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 type Remote struct {
 	ApiBaseUrl string
 	AccessKey  string
+	// Namespace scopes every request to one namespace. If empty, static and
+	// dynamic values of all namespaces are loaded.
+	Namespace  string
 	httpClient *http.Client
 	maxRetries int
 }
@@ -28,30 +33,96 @@ func NewRemote(apiBaseUrl, accessKey string) *Remote {
 	}
 }
 
+// WithNamespace returns a copy of the Remote scoped to namespace.
+func (c *Remote) WithNamespace(namespace string) Source {
+	scoped := *c
+	scoped.Namespace = namespace
+	return &scoped
+}
+
+// do sends the request built by newRequest, and a new one up to maxRetries
+// times while the server answers with a 5xx status. Each call counts its own
+// attempts, so concurrent requests don't use up each other's retries.
+func (c *Remote) do(newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil || resp.StatusCode < 500 || attempt >= c.maxRetries {
+			return resp, err
+		}
+		resp.Body.Close()
+	}
+}
+
+// url returns the URL of path, scoped to the Remote's namespace.
+func (c *Remote) url(path string, query url.Values) string {
+	if c.Namespace != "" {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("namespace", c.Namespace)
+	}
+	if len(query) == 0 {
+		return c.ApiBaseUrl + path
+	}
+	return c.ApiBaseUrl + path + "?" + query.Encode()
+}
+
+// objects flattens a response into objects. Values without a namespace
+// belong to the Remote's namespace.
+func (c *Remote) objects(data []response) []Object {
+	var result []Object
+	for _, d := range data {
+		namespace := d.Namespace
+		if namespace == "" {
+			namespace = c.Namespace
+		}
+		for _, v := range d.Value {
+			result = append(result, Object{
+				Namespace:  namespace,
+				LocaleCode: v.LocaleCode,
+				Key:        d.Key,
+				Value:      v.Value,
+				Revision:   v.Revision,
+			})
+		}
+	}
+	return result
+}
+
 type response struct {
+	Namespace   string `json:"namespace"`
 	Key         string `json:"key"`
 	HasComments bool   `json:"hasComments"`
 	Value       []struct {
 		Value       string `json:"value"`
 		LocaleCode  string `json:"locale"`
+		Revision    string `json:"revision"`
 		Status      string `json:"status"`
 		HasComments bool   `json:"hasComments"`
 	} `json:"values"`
 }
 
 func (c *Remote) LoadAllStatic(checksumIn string) (result []Object, checksumOut string, err error) {
+	return c.LoadAllStaticContext(context.Background(), checksumIn)
+}
 
-	url := fmt.Sprintf("%s/static/values", c.ApiBaseUrl)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, "", err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.AccessKey)
-	if checksumIn != "" {
-		req.Header.Set("If-None-Match", checksumIn)
-	}
+func (c *Remote) LoadAllStaticContext(ctx context.Context, checksumIn string) (result []Object, checksumOut string, err error) {
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.url("/static/values", nil), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+		if checksumIn != "" {
+			req.Header.Set("If-None-Match", checksumIn)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, "", err
 	}
@@ -61,11 +132,6 @@ func (c *Remote) LoadAllStatic(checksumIn string) (result []Object, checksumOut
 		return nil, checksumIn, nil
 	}
 
-	if resp.StatusCode >= 500 && c.maxRetries > 0 {
-		c.maxRetries--
-		return c.LoadAllStatic(checksumIn)
-	}
-
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, "", err
@@ -81,34 +147,29 @@ func (c *Remote) LoadAllStatic(checksumIn string) (result []Object, checksumOut
 		return nil, "", err
 	}
 
-	for _, d := range data {
-		for _, v := range d.Value {
-			result = append(result, Object{
-				LocaleCode: v.LocaleCode,
-				Key:        d.Key,
-				Value:      v.Value,
-			})
-		}
-	}
-
+	result = c.objects(data)
 	checksumOut = resp.Header.Get("ETag")
 	return result, checksumOut, nil
 }
 
 func (c *Remote) LoadAllDynamic(dynamicKey string, checksumIn string) (result []Object, checkSumOut string, err error) {
-	url := fmt.Sprintf("%s/dynamic/values", c.ApiBaseUrl)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, "", err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+	return c.LoadAllDynamicContext(context.Background(), dynamicKey, checksumIn)
+}
 
-	req.Header.Set("X-Dynamic-Key", dynamicKey)
-	if checksumIn != "" {
-		req.Header.Set("If-None-Match", checksumIn)
-	}
+func (c *Remote) LoadAllDynamicContext(ctx context.Context, dynamicKey string, checksumIn string) (result []Object, checkSumOut string, err error) {
+	resp, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.url("/dynamic/values", nil), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.AccessKey)
 
-	resp, err := c.httpClient.Do(req)
+		req.Header.Set("X-Dynamic-Key", dynamicKey)
+		if checksumIn != "" {
+			req.Header.Set("If-None-Match", checksumIn)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, "", err
 	}
@@ -119,11 +180,6 @@ func (c *Remote) LoadAllDynamic(dynamicKey string, checksumIn string) (result []
 		return nil, checksumIn, nil
 	}
 
-	if resp.StatusCode < 500 && c.maxRetries > 0 {
-		c.maxRetries--
-		return c.LoadAllDynamic(dynamicKey, checksumIn)
-	}
-
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, "", err
@@ -139,45 +195,40 @@ func (c *Remote) LoadAllDynamic(dynamicKey string, checksumIn string) (result []
 		return nil, "", err
 	}
 
-	for _, d := range data {
-		for _, v := range d.Value {
-			result = append(result, Object{
-				LocaleCode: v.LocaleCode,
-				Key:        d.Key,
-				Value:      v.Value,
-			})
-		}
-	}
-
+	result = c.objects(data)
 	checkSumOut = resp.Header.Get("ETag")
 	return result, checkSumOut, nil
 }
 
 func (c *Remote) LoadOneDynamic(dynamicKey, lang, key string) (string, error) {
-	url := fmt.Sprintf("%s/dynamic/value?lang=%s&key=%s", c.ApiBaseUrl, lang, key)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return key, err
-	}
+	return c.LoadOneDynamicContext(context.Background(), dynamicKey, lang, key)
+}
 
-	req.Header.Set("X-Dynamic-Key", dynamicKey)
-	req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+func (c *Remote) LoadOneDynamicContext(ctx context.Context, dynamicKey, lang, key string) (string, error) {
+	query := url.Values{"lang": {lang}, "key": {key}}
+	resp, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.url("/dynamic/value", query), nil)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := c.httpClient.Do(req)
+		req.Header.Set("X-Dynamic-Key", dynamicKey)
+		req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+		return req, nil
+	})
 	if err != nil {
 
 		return key, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-
-		return key, err
+	if resp.StatusCode == http.StatusNotFound {
+		return key, fmt.Errorf("key %s: %w", key, ErrNotFound)
 	}
 
-	if resp.StatusCode >= 500 && c.maxRetries > 0 {
-		c.maxRetries--
-		return c.LoadOneDynamic(dynamicKey, lang, key)
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return key, errors.New("Error from server returned: " + string(b))
 	}
 
 	var temp struct {
@@ -196,7 +247,90 @@ func (c *Remote) LoadOneDynamic(dynamicKey, lang, key string) (string, error) {
 	return temp.Value, err
 }
 
+func (c *Remote) LoadManyDynamic(dynamicKey, lang string, keys []string) (map[string]string, error) {
+	return c.LoadManyDynamicContext(context.Background(), dynamicKey, lang, keys)
+}
+
+// LoadManyDynamicContext fetches the values of keys in lang with one request
+// to the bulk endpoint, which answers like /dynamic/values for just those keys.
+// Servers without the endpoint make it fail with ErrBatchUnsupported.
+func (c *Remote) LoadManyDynamicContext(ctx context.Context, dynamicKey, lang string, keys []string) (map[string]string, error) {
+	var r = struct {
+		Lang string   `json:"lang"`
+		Keys []string `json:"keys"`
+	}{
+		Lang: lang,
+		Keys: keys,
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.url("/dynamic/values/batch", nil), bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Dynamic-Key", dynamicKey)
+		req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil, fmt.Errorf("%s: %w", resp.Status, ErrBatchUnsupported)
+	}
+
+	b, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("Error from server returned: " + string(b))
+	}
+
+	var data []response
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(keys))
+	for _, obj := range c.objects(data) {
+		if obj.LocaleCode == lang {
+			result[obj.Key] = obj.Value
+		}
+	}
+	return result, nil
+}
+
 func (c *Remote) SaveDynamic(dynamicKey string, data []Object) error {
+	return c.SaveDynamicContext(context.Background(), dynamicKey, data)
+}
+
+// SaveDynamicContext saves data with one PATCH request. The revisions of the
+// objects are sent along, and a single object's as If-Match; the server
+// refuses the whole request with 409 or 412 if any of them is outdated.
+func (c *Remote) SaveDynamicContext(ctx context.Context, dynamicKey string, data []Object) error {
+	if c.Namespace != "" {
+		scoped := make([]Object, len(data))
+		for i, datum := range data {
+			if datum.Namespace == "" {
+				datum.Namespace = c.Namespace
+			}
+			scoped[i] = datum
+		}
+		data = scoped
+	}
+
 	var r = struct {
 		Values []Object `json:"values"`
 	}{
@@ -209,15 +343,16 @@ func (c *Remote) SaveDynamic(dynamicKey string, data []Object) error {
 		return err
 	}
 
-	url := fmt.Sprintf("%s/dynamic/values", c.ApiBaseUrl)
-
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(b))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.url("/dynamic/values", nil), bytes.NewBuffer(b))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("X-Dynamic-Key", dynamicKey)
 	req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+	if len(data) == 1 && data[0].Revision != "" {
+		req.Header.Set("If-Match", data[0].Revision)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -231,6 +366,69 @@ func (c *Remote) SaveDynamic(dynamicKey string, data []Object) error {
 			return err
 		}
 
+		if resp.StatusCode == http.StatusConflict || resp.StatusCode == http.StatusPreconditionFailed {
+			return conflictError(b, data)
+		}
+		return errors.New("Error from server returned: " + string(b))
+	}
+	return nil
+}
+
+// conflictError reads the conflicts listed in the body of a refused save. If
+// the body lists none, every object of data with a revision is reported.
+func conflictError(body []byte, data []Object) *ConflictError {
+	var r struct {
+		Conflicts []Conflict `json:"conflicts"`
+	}
+	if json.Unmarshal(body, &r) == nil && len(r.Conflicts) > 0 {
+		return &ConflictError{Conflicts: r.Conflicts}
+	}
+
+	var conflicts []Conflict
+	for _, datum := range data {
+		if datum.Revision != "" {
+			conflicts = append(conflicts, Conflict{
+				Namespace:  datum.Namespace,
+				LocaleCode: datum.LocaleCode,
+				Key:        datum.Key,
+			})
+		}
+	}
+	return &ConflictError{Conflicts: conflicts}
+}
+
+func (c *Remote) ReportMissing(ctx context.Context, keys []MissingKey) error {
+	var r = struct {
+		Keys []MissingKey `json:"keys"`
+	}{
+		Keys: keys,
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url("/static/missing", nil), bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		b, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
 		return errors.New("Error from server returned: " + string(b))
 	}
 	return nil
@@ -1,5 +1,52 @@
 package source
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by LoadOneDynamic for a key the source has no value
+// for in the requested locale.
+var ErrNotFound = errors.New("not found")
+
+// ErrBatchUnsupported is returned by LoadManyDynamic when the server has no
+// endpoint for fetching many keys at once.
+var ErrBatchUnsupported = errors.New("batch requests are not supported")
+
+// ErrConflict is matched by the *ConflictError returned by SaveDynamic when
+// conditional writes were refused.
+var ErrConflict = errors.New("conflict")
+
+// Conflict is a value whose conditional write was refused because it changed
+// since the revision the write was based on.
+type Conflict struct {
+	Namespace  string `json:"namespace,omitempty"`
+	LocaleCode string `json:"localeCode"`
+	Key        string `json:"key"`
+	// Revision is the current revision of the value, "" if the source has none.
+	Revision string `json:"revision,omitempty"`
+}
+
+// ConflictError lists the values whose conditional writes were refused. A save
+// that conflicts writes none of its values.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	keys := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		keys[i] = c.LocaleCode + "/" + c.Key
+	}
+	return fmt.Sprintf("conflict: changed since read: %s", strings.Join(keys, ", "))
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
 type Source interface {
 	LoadAllStatic(checksumIn string) (result []Object, checksumOut string, err error)
 	LoadAllDynamic(dynamicKey string, checksumIn string) (result []Object, checkSumOut string, err error)
@@ -7,8 +54,88 @@ type Source interface {
 	SaveDynamic(accessKey string, data []Object) error
 }
 
+// ContextSource is implemented by sources that honour cancellation and deadlines.
+// Every method mirrors its Source counterpart with a leading context.Context.
+type ContextSource interface {
+	LoadAllStaticContext(ctx context.Context, checksumIn string) (result []Object, checksumOut string, err error)
+	LoadAllDynamicContext(ctx context.Context, dynamicKey string, checksumIn string) (result []Object, checkSumOut string, err error)
+	LoadOneDynamicContext(ctx context.Context, accessKey, lang, key string) (string, error)
+	SaveDynamicContext(ctx context.Context, accessKey string, data []Object) error
+}
+
+// NamespaceSource is implemented by sources whose requests can be scoped to a
+// namespace. The client of a namespace uses the scoped source for its dynamic
+// lookups and saves.
+type NamespaceSource interface {
+	WithNamespace(namespace string) Source
+}
+
+// BatchSource is implemented by sources that fetch the dynamic values of many
+// keys in one request. Keys the source has no value for in lang are left out
+// of the result.
+type BatchSource interface {
+	LoadManyDynamic(accessKey, lang string, keys []string) (map[string]string, error)
+	LoadManyDynamicContext(ctx context.Context, accessKey, lang string, keys []string) (map[string]string, error)
+}
+
 type Object struct {
+	// Namespace separates the keys of products sharing a source; it is empty
+	// for the shared namespace.
+	Namespace  string `json:"namespace,omitempty"`
 	LocaleCode string `json:"localeCode"`
 	Key        string `json:"key"`
 	Value      string `json:"value"`
+	// Revision identifies the version of Value loaded from the source. Saving
+	// an object with a Revision succeeds only if the value in the source still
+	// has that revision, and fails with a *ConflictError otherwise. Objects
+	// without one are saved unconditionally.
+	Revision string `json:"revision,omitempty"`
+}
+
+// LoadAllStatic calls s.LoadAllStaticContext when s implements ContextSource.
+// Otherwise the context is only checked before the call is made.
+func LoadAllStatic(ctx context.Context, s Source, checksumIn string) ([]Object, string, error) {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.LoadAllStaticContext(ctx, checksumIn)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	return s.LoadAllStatic(checksumIn)
+}
+
+// LoadAllDynamic calls s.LoadAllDynamicContext when s implements ContextSource.
+// Otherwise the context is only checked before the call is made.
+func LoadAllDynamic(ctx context.Context, s Source, dynamicKey string, checksumIn string) ([]Object, string, error) {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.LoadAllDynamicContext(ctx, dynamicKey, checksumIn)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	return s.LoadAllDynamic(dynamicKey, checksumIn)
+}
+
+// LoadOneDynamic calls s.LoadOneDynamicContext when s implements ContextSource.
+// Otherwise the context is only checked before the call is made.
+func LoadOneDynamic(ctx context.Context, s Source, accessKey, lang, key string) (string, error) {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.LoadOneDynamicContext(ctx, accessKey, lang, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return key, err
+	}
+	return s.LoadOneDynamic(accessKey, lang, key)
+}
+
+// SaveDynamic calls s.SaveDynamicContext when s implements ContextSource.
+// Otherwise the context is only checked before the call is made.
+func SaveDynamic(ctx context.Context, s Source, accessKey string, data []Object) error {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.SaveDynamicContext(ctx, accessKey, data)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.SaveDynamic(accessKey, data)
 }
@@ -1,7 +1,10 @@
 package word
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/summit-fi/wordsdk-go/fluent"
 	"github.com/summit-fi/wordsdk-go/fluent/cldr"
@@ -9,81 +12,236 @@ import (
 )
 
 func (c *Client) T(lang string, key string) string {
+	return c.TContext(context.Background(), lang, key)
+}
 
-	bundle := c.cache.Get(cldr.Language(lang))
+// TContext is T with a context. Static lookups are served from memory, so ctx
+// is accepted for API symmetry with the dynamic path.
+func (c *Client) TContext(ctx context.Context, lang string, key string) string {
+	message, _ := c.TEContext(ctx, lang, key)
+	return message
+}
 
-	if bundle == nil {
-		c.logger.Debugf("Bundle for language '%s' is nil, returning key: %s", lang, key)
-		return key
-	}
+// TE is T returning the diagnostics of the lookup as well. The message is the
+// same T would return, so it may be partial or the key; the error is an
+// ErrMissingMessage if no bundle has key and otherwise joins the
+// *fluent.ResolveError of every placeable that could not be resolved, e.g.
+//
+//	msg, err := c.TAE("en_US", "greeting", args)
+//	if errors.Is(err, fluent.ErrUnknownVariable) { ... }
+func (c *Client) TE(lang string, key string) (string, error) {
+	return c.TEContext(context.Background(), lang, key)
+}
+
+func (c *Client) TEContext(ctx context.Context, lang string, key string) (string, error) {
+	return c.TAEContext(ctx, lang, key, nil)
+}
 
-	message, errs, err := bundle.FormatMessage(key)
+// formatMessage formats key with bundle and falls back to the key on failure.
+func (c *Client) formatMessage(bundle *fluent.Bundle, key string, contexts ...*fluent.FormatContext) string {
+	message, _ := c.formatMessageError(bundle, key, contexts...)
+	return message
+}
+
+// formatMessageError is formatMessage returning the resolver's errors joined.
+func (c *Client) formatMessageError(bundle *fluent.Bundle, key string, contexts ...*fluent.FormatContext) (string, error) {
+	message, errs, err := bundle.FormatMessage(key, contexts...)
+	if err != nil || len(errs) > 0 {
+		c.metrics.ResolverError(bundle.PrimaryLocale())
+	}
 	if err != nil {
-		c.logger.Debugf("Failed to format message for key '%s': %v, stack:%v", key, err, errs)
-		return key
+		c.logLookup(slog.LevelDebug, "Failed to format message",
+			slog.String("lang", string(bundle.PrimaryLocale())),
+			slog.String("key", key),
+			slog.Any("error", err),
+			slog.Any("errors", errs))
+		return key, err
+	}
+	if len(errs) > 0 {
+		c.logLookup(slog.LevelWarn, "Message formatted with errors",
+			slog.String("lang", string(bundle.PrimaryLocale())),
+			slog.String("key", key),
+			slog.Any("errors", errs))
+	}
+	if c.isPseudo(bundle) {
+		message = fluent.PseudoMessage(message)
 	}
 
-	return message
+	return message, errors.Join(errs...)
 }
 
 func (c *Client) TA(lang string, key string, args any) string {
-	// temporary
-	bundle := c.cache.Get(cldr.Language(lang))
+	return c.TAContext(context.Background(), lang, key, args)
+}
 
+func (c *Client) TAContext(ctx context.Context, lang string, key string, args any) string {
+	message, _ := c.TAEContext(ctx, lang, key, args)
+	return message
+}
+
+// TAE is TA returning the diagnostics of the lookup as well, see TE.
+func (c *Client) TAE(lang string, key string, args any) (string, error) {
+	return c.TAEContext(context.Background(), lang, key, args)
+}
+
+func (c *Client) TAEContext(ctx context.Context, lang string, key string, args any) (string, error) {
+	c.metrics.Lookup(c.metricLocale(cldr.Language(lang)))
+
+	bundle := c.resolveBundle(cldr.Language(lang), key)
+
+	var message string
+	var err error
 	if bundle == nil {
-		c.logger.Debugf("Bundle for language '%s' is nil, returning key: %s", lang, key)
-		return key
+		message, err = c.missingError(lang, key, args)
+	} else {
+		message, err = c.formatMessageError(bundle, key, fluent.WithArgs(args))
 	}
+	return c.reveal(ctx, key, cldr.Language(lang), bundle, message), err
+}
 
-	switch args.(type) {
-	case map[string]any:
-		message, errs, err := bundle.FormatMessage(key, fluent.WithVariables(args.(map[string]any)))
-		if err != nil {
-			c.logger.Debugf("Failed to format message for key '%s': %v, stack:%v", key, err, errs)
-			return key
-		}
-		return message
-	default:
-		c.logger.Debugf("TA function expects a map[string]any for args, got %T", args)
-		return key
+// TM returns the value and attributes of the message key, e.g. the .title and
+// .placeholder of a form field, with the fallbacks of T. If the message is
+// missing, the value is the key or the MissingKeyHandler's replacement.
+func (c *Client) TM(lang string, key string, args any) *fluent.FormattedMessage {
+	return c.TMContext(context.Background(), lang, key, args)
+}
+
+func (c *Client) TMContext(ctx context.Context, lang string, key string, args any) *fluent.FormattedMessage {
+	c.metrics.Lookup(c.metricLocale(cldr.Language(lang)))
+
+	bundle := c.resolveBundle(cldr.Language(lang), key)
+	if bundle == nil {
+		return c.missingMessage(lang, key, args)
+	}
+	return c.formatFullMessage(bundle, key, fluent.WithArgs(args))
+}
+
+// TAttr returns the attribute attr of the message key. If the message or the
+// attribute is missing, it returns the MissingKeyHandler's replacement for
+// key.attr or key.attr itself.
+func (c *Client) TAttr(lang string, key string, attr string, args any) string {
+	return c.TAttrContext(context.Background(), lang, key, attr, args)
+}
+
+func (c *Client) TAttrContext(ctx context.Context, lang string, key string, attr string, args any) string {
+	return c.attr(lang, key, attr, args, c.TMContext(ctx, lang, key, args))
+}
+
+// attr returns the attribute attr of message, reporting it as missing if absent.
+func (c *Client) attr(lang, key, attr string, args any, message *fluent.FormattedMessage) string {
+	if v, ok := message.Attributes[attr]; ok {
+		return v
 	}
+	return c.missing(lang, key+"."+attr, args)
+}
 
+// formatFullMessage formats key and its attributes with bundle. On failure the
+// value is the key and there are no attributes.
+func (c *Client) formatFullMessage(bundle *fluent.Bundle, key string, contexts ...*fluent.FormatContext) *fluent.FormattedMessage {
+	message, errs, err := bundle.FormatFullMessage(key, contexts...)
+	if err != nil || len(errs) > 0 {
+		c.metrics.ResolverError(bundle.PrimaryLocale())
+	}
+	if err != nil {
+		c.logLookup(slog.LevelDebug, "Failed to format message",
+			slog.String("lang", string(bundle.PrimaryLocale())),
+			slog.String("key", key),
+			slog.Any("error", err),
+			slog.Any("errors", errs))
+		return &fluent.FormattedMessage{Value: &key, Attributes: map[string]string{}}
+	}
+	if len(errs) > 0 {
+		c.logLookup(slog.LevelWarn, "Message formatted with errors",
+			slog.String("lang", string(bundle.PrimaryLocale())),
+			slog.String("key", key),
+			slog.Any("errors", errs))
+	}
+	if c.isPseudo(bundle) {
+		pseudoFormattedMessage(message)
+	}
+	message.Revision = c.revision(bundle, key)
+	return message
+}
+
+// missingMessage is missing for TM.
+func (c *Client) missingMessage(lang, key string, args any) *fluent.FormattedMessage {
+	value := c.missing(lang, key, args)
+	return &fluent.FormattedMessage{Value: &value, Attributes: map[string]string{}}
+}
+
+// missingError is missing for TE, also returning an ErrMissingMessage.
+func (c *Client) missingError(lang, key string, args any) (string, error) {
+	return c.missing(lang, key, args), &fluent.ResolveError{
+		Kind:   fluent.ErrMissingMessage,
+		Key:    key,
+		Locale: cldr.Language(lang),
+	}
 }
 
 func (c *Client) SaveTranslations(data []source.Object) error {
+	return c.SaveTranslationsContext(context.Background(), data)
+}
+
+func (c *Client) SaveTranslationsContext(ctx context.Context, data []source.Object) error {
 	return fmt.Errorf("use DynamicContent.SaveTranslations() instead of Client.SaveTranslations()")
 }
 
 func (c *Client) SaveTranslation(lang string, key string, value string) error {
+	return c.SaveTranslationContext(context.Background(), lang, key, value)
+}
+
+func (c *Client) SaveTranslationContext(ctx context.Context, lang string, key string, value string) error {
 	return fmt.Errorf("use DynamicContent.SaveTranslation() instead of Client.SaveTranslation()")
 }
 
 func (c *Client) Reset() error {
+	return c.ResetContext(context.Background())
+}
 
-	static, _, err := c.source.LoadAllStatic("")
+func (c *Client) ResetContext(ctx context.Context) error {
+
+	data, _, err := source.LoadAllStatic(ctx, c.source, "")
 	if err != nil {
 		return err
 	}
-
-	for _, locale := range c.cache.GetKeys() {
-		bundle := c.cache.Get(cldr.Language(locale))
-		if bundle == nil {
-			return fmt.Errorf("no bundle found for language '%s'", locale)
-		}
-		bundle = fluent.NewBundle(cldr.Language(locale))
-		c.cache.Set(cldr.Language(locale), bundle)
-
-		c.logger.Debugf("Reset bundle for language '%s'", locale)
+	shared, namespaced := splitNamespaces(data)
+	static := newCatalogValues(shared)
+	if c.shared != nil {
+		// A namespace keeps its own objects, whether or not the source scoped them.
+		static = namespaced[c.namespace]
+	} else {
+		c.setNamespaces(namespaced, true)
 	}
 
-	err = c.UpdateBundle(static)
+	// Rebuild every bundle from the source alone, dropping saved dynamic values.
+	err = c.updateCatalog(func(cur *catalog) (*catalog, error) {
+		c.resetSaved()
+		cat, err := newCatalog(c.cache, cur, static, cur.dynamic, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.shared == nil {
+			cat.revisions = newRevisions(data)
+		}
+		return cat, nil
+	})
 	if err != nil {
 		return err
 	}
 
+	c.dynamicCache.clear()
+
+	for _, locale := range c.catalog().locales() {
+		c.log(slog.LevelDebug, "Bundle reset", slog.String("lang", string(locale)))
+	}
+
 	return nil
 }
 
 func (c *Client) Flush() error {
+	return c.FlushContext(context.Background())
+}
+
+func (c *Client) FlushContext(ctx context.Context) error {
 	return fmt.Errorf("use DynamicContent.Flush() instead of Client.Flush()")
 }
@@ -0,0 +1,183 @@
+package word
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+// defaultMaxBackoff caps the delay between failed syncs when Config.MaxBackoff is unset.
+const defaultMaxBackoff = 5 * time.Minute
+
+// Status describes the health of the client's sync with its source.
+type Status struct {
+	// Ready is set once a catalog is served, loaded from the source or from a
+	// snapshot.
+	Ready bool `json:"ready"`
+	// FromSnapshot is set while the catalog served is the snapshot's, until
+	// the first sync that reaches the source.
+	FromSnapshot bool `json:"fromSnapshot,omitempty"`
+	// Checksum is the checksum of the static catalog currently served.
+	Checksum string `json:"checksum"`
+	// LastSuccess is the time of the last sync that reached the source.
+	LastSuccess time.Time `json:"lastSuccess"`
+	// LastError is the error of the last sync, empty if it succeeded.
+	LastError string `json:"lastError,omitempty"`
+	// ConsecutiveFailures counts the syncs failed since the last success.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// NextSync is the time the next sync is scheduled, zero if there is none.
+	NextSync time.Time `json:"nextSync,omitempty"`
+	// Keys is the number of keys served per locale.
+	Keys map[cldr.Language]int `json:"keys"`
+}
+
+// syncStatus is the mutable state behind Status, written by the sync loop.
+type syncStatus struct {
+	mu                  sync.Mutex
+	checksum            string
+	lastSuccess         time.Time
+	lastErr             error
+	consecutiveFailures int
+	nextSync            time.Time
+	fromSnapshot        bool
+	// ready is closed once a catalog is served: on the first successful sync
+	// or when a snapshot is loaded.
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newSyncStatus() *syncStatus {
+	return &syncStatus{ready: make(chan struct{})}
+}
+
+func (s *syncStatus) success(checksum string) {
+	s.mu.Lock()
+	s.checksum = checksum
+	s.lastSuccess = time.Now()
+	s.lastErr = nil
+	s.consecutiveFailures = 0
+	s.fromSnapshot = false
+	s.mu.Unlock()
+
+	s.readyOnce.Do(func() { close(s.ready) })
+}
+
+// failure records err and returns the number of consecutive failures.
+func (s *syncStatus) failure(err error) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	s.consecutiveFailures++
+	return s.consecutiveFailures
+}
+
+func (s *syncStatus) scheduled(next time.Time) {
+	s.mu.Lock()
+	s.nextSync = next
+	s.mu.Unlock()
+}
+
+// snapshot records that the catalog of a snapshot with checksum is served
+// until the first successful sync.
+func (s *syncStatus) snapshot(checksum string) {
+	s.mu.Lock()
+	s.checksum = checksum
+	s.fromSnapshot = true
+	s.mu.Unlock()
+
+	s.readyOnce.Do(func() { close(s.ready) })
+}
+
+// Status returns the health of the sync with the source.
+func (c *Client) Status() Status {
+	c.status.mu.Lock()
+	st := Status{
+		Checksum:            c.status.checksum,
+		LastSuccess:         c.status.lastSuccess,
+		ConsecutiveFailures: c.status.consecutiveFailures,
+		NextSync:            c.status.nextSync,
+		FromSnapshot:        c.status.fromSnapshot,
+	}
+	if c.status.lastErr != nil {
+		st.LastError = c.status.lastErr.Error()
+	}
+	c.status.mu.Unlock()
+
+	select {
+	case <-c.status.ready:
+		st.Ready = true
+	default:
+	}
+
+	cat := c.catalog()
+	st.Keys = make(map[cldr.Language]int, len(cat.entries))
+	for _, l := range cat.locales() {
+		n := len(cat.static[l])
+		for key := range cat.saved[l] {
+			if _, ok := cat.static[l][key]; !ok {
+				n++
+			}
+		}
+		st.Keys[l] = n
+	}
+	return st
+}
+
+// WaitReady blocks until a catalog is served, loaded from the source or from a
+// snapshot, or ctx expires, in which case ctx.Err() is returned.
+func (c *Client) WaitReady(ctx context.Context) error {
+	select {
+	case <-c.status.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HealthHandler returns a handler for readiness probes. It responds with the
+// Status as JSON, with 200 OK when the client is ready and 503 otherwise.
+func (c *Client) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st := c.Status()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if st.Ready {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(st); err != nil {
+			c.log(slog.LevelDebug, "Failed to write health status", slog.Any("error", err))
+		}
+	})
+}
+
+// nextDelay returns the delay before the next sync. After failures the update
+// interval is doubled per consecutive failure up to maxBackoff, and jittered
+// between half and the full delay so clients don't retry in lockstep.
+func (c *Client) nextDelay(failures int) time.Duration {
+	if failures == 0 {
+		return c.updateInterval
+	}
+
+	maxBackoff := c.maxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	maxBackoff = max(maxBackoff, c.updateInterval)
+
+	delay := c.updateInterval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
@@ -0,0 +1,139 @@
+package word
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+func TestClient_Status(t *testing.T) {
+	src := &stubSource{
+		objects: []source.Object{
+			{LocaleCode: "en_US", Key: "hello", Value: "Hello"},
+			{LocaleCode: "en_US", Key: "bye", Value: "Bye"},
+			{LocaleCode: "uk_UA", Key: "hello", Value: "Привіт"},
+		},
+		checksum: "v1",
+	}
+	sdk, err := NewClient(&Config{Source: src})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	c := sdk.(*Client)
+	defer c.Close(context.Background())
+
+	st := c.Status()
+	if !st.Ready || st.Checksum != "v1" || st.ConsecutiveFailures != 0 || st.LastError != "" {
+		t.Errorf("Status() = %+v, want ready at v1 without failures", st)
+	}
+	if st.LastSuccess.IsZero() {
+		t.Error("Status().LastSuccess is zero")
+	}
+	if st.Keys["en_US"] != 2 || st.Keys["uk_UA"] != 1 {
+		t.Errorf("Status().Keys = %v, want en_US: 2, uk_UA: 1", st.Keys)
+	}
+
+	src.mu.Lock()
+	src.err = errors.New("word api is down")
+	src.mu.Unlock()
+	c.syncTranslations(context.Background())
+	c.syncTranslations(context.Background())
+
+	st = c.Status()
+	if st.ConsecutiveFailures != 2 || st.LastError != "word api is down" || st.Checksum != "v1" {
+		t.Errorf("Status() = %+v, want 2 failures keeping checksum v1", st)
+	}
+
+	src.mu.Lock()
+	src.err = nil
+	src.mu.Unlock()
+	c.syncTranslations(context.Background())
+
+	if st = c.Status(); st.ConsecutiveFailures != 0 || st.LastError != "" {
+		t.Errorf("Status() = %+v, want failures reset after a successful sync", st)
+	}
+}
+
+func TestClient_NextDelay(t *testing.T) {
+	c := &Client{updateInterval: time.Second, maxBackoff: 10 * time.Second}
+
+	if got := c.nextDelay(0); got != time.Second {
+		t.Errorf("nextDelay(0) = %v, want the update interval", got)
+	}
+	tests := []struct {
+		failures int
+		min, max time.Duration
+	}{
+		{1, time.Second, 2 * time.Second},
+		{2, 2 * time.Second, 4 * time.Second},
+		{3, 4 * time.Second, 8 * time.Second},
+		{4, 5 * time.Second, 10 * time.Second},
+		{50, 5 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if got := c.nextDelay(tt.failures); got < tt.min || got > tt.max {
+				t.Errorf("nextDelay(%d) = %v, want between %v and %v", tt.failures, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestClient_ReadyFromSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	online := &stubSource{
+		objects:  []source.Object{{LocaleCode: "en_US", Key: "hello", Value: "Hello"}},
+		checksum: "v1",
+	}
+	sdk, err := NewClient(&Config{Source: online, SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	sdk.Close(context.Background())
+
+	offline := &stubSource{err: errors.New("word api is down")}
+	sdk, err = NewClient(&Config{Source: offline, SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer sdk.Close(context.Background())
+
+	// The snapshot is served, so the client is ready, if stale.
+	if err := sdk.WaitReady(context.Background()); err != nil {
+		t.Errorf("WaitReady() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	sdk.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("health status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var st Status
+	if err := json.NewDecoder(rec.Body).Decode(&st); err != nil {
+		t.Fatalf("decode health body: %v", err)
+	}
+	if !st.Ready || !st.FromSnapshot || st.Checksum != "v1" || !st.LastSuccess.IsZero() {
+		t.Errorf("health body = %+v, want ready from the snapshot with checksum v1", st)
+	}
+
+	// Once the source is back, the revalidation clears FromSnapshot.
+	// Wait for the failed background revalidation to finish first.
+	<-sdk.(*Client).done
+	offline.mu.Lock()
+	offline.err = nil
+	offline.checksum = "v1"
+	offline.mu.Unlock()
+	sdk.(*Client).syncTranslations(context.Background())
+
+	if st := sdk.Status(); !st.Ready || st.FromSnapshot || st.LastSuccess.IsZero() {
+		t.Errorf("Status() = %+v, want ready and revalidated", st)
+	}
+}
@@ -0,0 +1,128 @@
+package word
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// UpdateEvent describes a catalog change applied by the sync loop.
+type UpdateEvent struct {
+	// Namespace is the namespace whose values changed, empty for the shared
+	// namespace. A sync sends one event per changed namespace.
+	Namespace   string
+	OldChecksum string
+	NewChecksum string
+	// Locales holds the changes per locale; locales without changes are omitted.
+	Locales map[cldr.Language]LocaleChanges
+}
+
+// LocaleChanges lists the keys of one locale that were added, changed or removed.
+type LocaleChanges struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// catalogValues holds the raw values of a catalog, per locale and key.
+type catalogValues map[cldr.Language]map[string]string
+
+// OnUpdate registers fn to be called after every sync that changed the catalog.
+// fn runs on the sync goroutine, so it should return quickly. Registered on a
+// namespace, fn receives the changes of the namespace and of the shared
+// namespace its lookups fall back to.
+func (c *Client) OnUpdate(fn func(ev UpdateEvent)) {
+	if c.shared != nil {
+		name := c.namespace
+		c.shared.OnUpdate(func(ev UpdateEvent) {
+			if ev.Namespace == name || ev.Namespace == "" {
+				fn(ev)
+			}
+		})
+		return
+	}
+
+	c.listenersMu.Lock()
+	c.listeners = append(c.listeners, fn)
+	c.listenersMu.Unlock()
+}
+
+func (c *Client) notifyUpdate(ev UpdateEvent) {
+	c.listenersMu.Lock()
+	listeners := append([]func(UpdateEvent){}, c.listeners...)
+	c.listenersMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(ev)
+	}
+}
+
+// newCatalogValues indexes data by locale and key, the way UpdateBundle reads it.
+func newCatalogValues(data []source.Object) catalogValues {
+	values := make(catalogValues)
+	for _, item := range data {
+		key := strings.TrimSpace(item.Key)
+		if strings.ContainsAny(key, "\n\r") {
+			continue
+		}
+		lang := cldr.Language(item.LocaleCode)
+		if values[lang] == nil {
+			values[lang] = make(map[string]string)
+		}
+		values[lang][key] = item.Value
+	}
+	return values
+}
+
+// diff returns the changes that turn old into v, per locale.
+func (v catalogValues) diff(old catalogValues) map[cldr.Language]LocaleChanges {
+	result := make(map[cldr.Language]LocaleChanges)
+
+	for lang, values := range v {
+		var changes LocaleChanges
+		for key, value := range values {
+			prev, ok := old[lang][key]
+			if !ok {
+				changes.Added = append(changes.Added, key)
+			} else if prev != value {
+				changes.Changed = append(changes.Changed, key)
+			}
+		}
+		for key := range old[lang] {
+			if _, ok := values[key]; !ok {
+				changes.Removed = append(changes.Removed, key)
+			}
+		}
+		if !changes.empty() {
+			result[lang] = changes.sorted()
+		}
+	}
+
+	for lang, values := range old {
+		if _, ok := v[lang]; ok {
+			continue
+		}
+		var changes LocaleChanges
+		for key := range values {
+			changes.Removed = append(changes.Removed, key)
+		}
+		if !changes.empty() {
+			result[lang] = changes.sorted()
+		}
+	}
+
+	return result
+}
+
+func (lc LocaleChanges) empty() bool {
+	return len(lc.Added) == 0 && len(lc.Changed) == 0 && len(lc.Removed) == 0
+}
+
+func (lc LocaleChanges) sorted() LocaleChanges {
+	sort.Strings(lc.Added)
+	sort.Strings(lc.Changed)
+	sort.Strings(lc.Removed)
+	return lc
+}
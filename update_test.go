@@ -0,0 +1,51 @@
+package word
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/summit-fi/wordsdk-go/fluent/cldr"
+)
+
+func TestClient_OnUpdate(t *testing.T) {
+	c, paths := tempFtlClient(t, Config{}, map[string]string{
+		"en_US": "kept = Kept\nchanged = Old\nremoved = Removed\n",
+		"uk_UA": "gone = Зникне\n",
+	})
+	defer c.Close(context.Background())
+
+	var events []UpdateEvent
+	c.OnUpdate(func(ev UpdateEvent) {
+		events = append(events, ev)
+	})
+
+	oldChecksum := c.checksum
+	if err := os.WriteFile(paths["en_US"], []byte("kept = Kept\nchanged = New\nadded = Added\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(paths["uk_UA"], []byte(""), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	c.syncTranslations(context.Background())
+	// Nothing changed since the last sync, so no event is sent.
+	c.syncTranslations(context.Background())
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.OldChecksum != oldChecksum || ev.NewChecksum != c.checksum || ev.OldChecksum == ev.NewChecksum {
+		t.Errorf("checksums = %q -> %q, want %q -> %q", ev.OldChecksum, ev.NewChecksum, oldChecksum, c.checksum)
+	}
+
+	want := map[cldr.Language]LocaleChanges{
+		cldr.LanguageEnUS: {Added: []string{"added"}, Changed: []string{"changed"}, Removed: []string{"removed"}},
+		cldr.LanguageUkUa: {Removed: []string{"gone"}},
+	}
+	if !reflect.DeepEqual(ev.Locales, want) {
+		t.Errorf("Locales = %+v, want %+v", ev.Locales, want)
+	}
+}
@@ -0,0 +1,299 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+const (
+	// defaultFlushBatchSize is the number of values saved per request when
+	// Config.FlushBatchSize is zero.
+	defaultFlushBatchSize = 100
+	// flushAttempts is the number of times a batch is sent before its values
+	// are reported as failed; retries wait flushRetryDelay, doubled each time.
+	flushAttempts   = 3
+	flushRetryDelay = 50 * time.Millisecond
+)
+
+// WriteResult is the outcome of saving one dirty value.
+type WriteResult struct {
+	Object source.Object
+	Err    error
+}
+
+// FlushError is returned by Flush when some dirty values could not be saved.
+// They stay queued and are retried by the next flush, unless they were saved
+// again in the meantime. Values refused with a *source.ConflictError are
+// dropped instead, as retrying them can't succeed.
+type FlushError struct {
+	// Saved is the number of values that were saved.
+	Saved  int
+	Failed []WriteResult
+}
+
+func (e *FlushError) Error() string {
+	return fmt.Sprintf("failed to save %d of %d pending translations: %v",
+		len(e.Failed), e.Saved+len(e.Failed), e.Failed[0].Err)
+}
+
+// Unwrap returns the errors of the failed values, those of consecutive values
+// failed by the same request once.
+func (e *FlushError) Unwrap() []error {
+	var errs []error
+	for _, r := range e.Failed {
+		// errors.Is only compares comparable errors, unlike ==.
+		if len(errs) == 0 || !errors.Is(errs[len(errs)-1], r.Err) {
+			errs = append(errs, r.Err)
+		}
+	}
+	return errs
+}
+
+// writeQueue holds the values saved under SaveStrategyOnDemand that are not in
+// the source yet: the latest raw value of every dirty locale and key.
+type writeQueue struct {
+	mu    sync.Mutex
+	seq   uint64
+	dirty map[writeKey]queuedWrite
+}
+
+type writeKey struct {
+	lang, key string
+}
+
+// queuedWrite is a dirty value; seq orders the writes and tells whether the
+// value was saved again while it was being flushed.
+type queuedWrite struct {
+	object source.Object
+	seq    uint64
+}
+
+// add marks the values of data dirty and returns the number of dirty values.
+func (q *writeQueue) add(data []source.Object) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.dirty == nil {
+		q.dirty = make(map[writeKey]queuedWrite)
+	}
+	for _, item := range data {
+		q.seq++
+		q.dirty[writeKey{item.LocaleCode, item.Key}] = queuedWrite{object: item, seq: q.seq}
+	}
+	return len(q.dirty)
+}
+
+// writes returns the dirty values, oldest first.
+func (q *writeQueue) writes() []queuedWrite {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	writes := make([]queuedWrite, 0, len(q.dirty))
+	for _, w := range q.dirty {
+		writes = append(writes, w)
+	}
+	sort.Slice(writes, func(i, j int) bool { return writes[i].seq < writes[j].seq })
+	return writes
+}
+
+// done marks the values of writes clean, unless they were saved again since.
+func (q *writeQueue) done(writes []queuedWrite) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, w := range writes {
+		k := writeKey{w.object.LocaleCode, w.object.Key}
+		if q.dirty[k].seq == w.seq {
+			delete(q.dirty, k)
+		}
+	}
+}
+
+// len returns the number of dirty values.
+func (q *writeQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.dirty)
+}
+
+// enqueueWrites queues data for the next flush and starts one in the
+// background once Config.FlushThreshold values are dirty.
+func (c *Client) enqueueWrites(data []source.Object) {
+	n := c.writes.add(data)
+	root := c.root()
+	if root.flushThreshold > 0 && n >= root.flushThreshold {
+		select {
+		case root.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// flushWrites saves the dirty values in batches of Config.FlushBatchSize. The
+// values of batches that failed every attempt stay dirty and are reported in
+// a *FlushError.
+func (c *Client) flushWrites(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	writes := c.writes.writes()
+	if len(writes) == 0 {
+		return nil
+	}
+
+	size := c.flushBatchSize
+	if size <= 0 {
+		size = defaultFlushBatchSize
+	}
+
+	var (
+		saved  int
+		failed []WriteResult
+	)
+	for start := 0; start < len(writes); start += size {
+		batch := writes[start:min(start+size, len(writes))]
+		for len(batch) > 0 {
+			err := c.saveBatch(ctx, objectsOf(batch))
+			if err == nil {
+				c.writes.done(batch)
+				saved += len(batch)
+				break
+			}
+
+			// A conflict saves nothing, so the rest of the batch is sent again
+			// without the conflicting values.
+			var conflict *source.ConflictError
+			if errors.As(err, &conflict) {
+				rejected, rest, listed := splitConflicts(batch, conflict)
+				c.writes.done(rejected)
+				c.dropSaved(objectsOf(rejected))
+				for _, w := range rejected {
+					failed = append(failed, WriteResult{Object: w.object, Err: err})
+				}
+				if listed {
+					batch = rest
+					continue
+				}
+				// Nothing says the other values conflicted, but they weren't
+				// saved either: they stay dirty for the next flush.
+				for _, w := range rest {
+					failed = append(failed, WriteResult{Object: w.object, Err: err})
+				}
+				break
+			}
+
+			for _, w := range batch {
+				failed = append(failed, WriteResult{Object: w.object, Err: err})
+			}
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return &FlushError{Saved: saved, Failed: failed}
+	}
+	c.log(slog.LevelDebug, "Pending translations saved", slog.Int("count", saved))
+	return nil
+}
+
+func objectsOf(writes []queuedWrite) []source.Object {
+	objects := make([]source.Object, len(writes))
+	for i, w := range writes {
+		objects[i] = w.object
+	}
+	return objects
+}
+
+// splitConflicts separates the writes listed in conflict from the others. If it
+// lists none of them, listed is false and the writes with a Revision are
+// rejected, since only those could have conflicted; the others are left in
+// rest, not to be sent again in this flush.
+func splitConflicts(writes []queuedWrite, conflict *source.ConflictError) (rejected, rest []queuedWrite, listed bool) {
+	conflicting := make(map[writeKey]struct{}, len(conflict.Conflicts))
+	for _, c := range conflict.Conflicts {
+		conflicting[writeKey{c.LocaleCode, c.Key}] = struct{}{}
+	}
+	for _, w := range writes {
+		if _, ok := conflicting[writeKey{w.object.LocaleCode, w.object.Key}]; ok {
+			rejected = append(rejected, w)
+		} else {
+			rest = append(rest, w)
+		}
+	}
+	if len(rejected) > 0 {
+		return rejected, rest, true
+	}
+
+	rest = nil
+	for _, w := range writes {
+		if w.object.Revision != "" {
+			rejected = append(rejected, w)
+		} else {
+			rest = append(rest, w)
+		}
+	}
+	return rejected, rest, false
+}
+
+// saveBatch saves objects, retrying with backoff up to flushAttempts times.
+// Conflicts are not retried.
+func (c *Client) saveBatch(ctx context.Context, objects []source.Object) error {
+	delay := flushRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := source.SaveDynamic(ctx, c.source, c.accessKey(), objects)
+		if err == nil || attempt == flushAttempts || ctx.Err() != nil || errors.Is(err, source.ErrConflict) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+// runFlushJob flushes the dirty values of the client and its namespaces every
+// Config.FlushInterval and whenever Config.FlushThreshold is reached, until
+// the client is closed.
+func (c *Client) runFlushJob() {
+	c.flushDone = make(chan struct{})
+	go func() {
+		defer close(c.flushDone)
+
+		var tick <-chan time.Time
+		if c.flushInterval > 0 {
+			ticker := time.NewTicker(c.flushInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-tick:
+			case <-c.flushNow:
+			}
+			c.flushAll(c.ctx)
+		}
+	}()
+}
+
+// flushAll flushes the client and its namespaces, logging failures.
+func (c *Client) flushAll(ctx context.Context) {
+	for _, client := range append([]*Client{c}, c.namespaceClients()...) {
+		if err := client.flushWrites(ctx); err != nil && ctx.Err() == nil {
+			c.log(slog.LevelError, "Failed to flush dynamic content",
+				slog.String("namespace", client.namespace),
+				slog.Int("pending", client.writes.len()),
+				slog.Any("error", err))
+		}
+	}
+}
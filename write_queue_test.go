@@ -0,0 +1,173 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/summit-fi/wordsdk-go/source"
+)
+
+// saveStub records every SaveDynamic call and fails those fail returns an
+// error for.
+type saveStub struct {
+	stubSource
+	saveMu  sync.Mutex
+	batches [][]source.Object
+	fail    func(data []source.Object) error
+}
+
+func (s *saveStub) SaveDynamic(accessKey string, data []source.Object) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	s.batches = append(s.batches, data)
+	if s.fail != nil {
+		return s.fail(data)
+	}
+	return nil
+}
+
+func (s *saveStub) saved() [][]source.Object {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	return append([][]source.Object(nil), s.batches...)
+}
+
+func newQueueClient(t *testing.T, config Config, src *saveStub) *DynamicContent {
+	t.Helper()
+	src.objects = []source.Object{{LocaleCode: "en_US", Key: "static", Value: "Static"}}
+	src.checksum = "v1"
+	config.Source = src
+	config.SaveStrategy = SaveStrategyOnDemand
+	sdk, err := NewClient(&config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { sdk.Close(context.Background()) })
+	return sdk.Dynamic()
+}
+
+func object(key, value string) source.Object {
+	return source.Object{LocaleCode: "en_US", Key: key, Value: value}
+}
+
+func TestDynamicContent_FlushDirtyValues(t *testing.T) {
+	src := &saveStub{}
+	d := newQueueClient(t, Config{FlushBatchSize: 2}, src)
+
+	items := "{ $count ->\n    [one] item\n   *[other] items\n}"
+	for _, o := range []source.Object{object("a", "A"), object("items", items), object("c", "C"), object("a", "A2")} {
+		if err := d.SaveTranslation(o.LocaleCode, o.Key, o.Value); err != nil {
+			t.Fatalf("SaveTranslation() error = %v", err)
+		}
+	}
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// Only the dirty keys are saved, with their raw values and latest writes.
+	want := [][]source.Object{{object("items", items), object("c", "C")}, {object("a", "A2")}}
+	if got := src.saved(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("second Flush() error = %v", err)
+	}
+	if got := len(src.saved()); got != 2 {
+		t.Errorf("batches after second Flush = %d, want 2", got)
+	}
+}
+
+func TestDynamicContent_FlushPartialFailure(t *testing.T) {
+	errDown := errors.New("down")
+	src := &saveStub{fail: func(data []source.Object) error {
+		if data[0].Key == "b" {
+			return errDown
+		}
+		return nil
+	}}
+	d := newQueueClient(t, Config{FlushBatchSize: 1}, src)
+	d.SaveTranslations([]source.Object{object("a", "A"), object("b", "B"), object("c", "C")})
+
+	err := d.Flush()
+	var flushErr *FlushError
+	if !errors.As(err, &flushErr) || !errors.Is(err, errDown) {
+		t.Fatalf("Flush() error = %v, want a *FlushError wrapping errDown", err)
+	}
+	if flushErr.Saved != 2 || len(flushErr.Failed) != 1 || flushErr.Failed[0].Object != object("b", "B") {
+		t.Errorf("FlushError = %+v, want a and c saved and b failed", flushErr)
+	}
+	if got := len(src.saved()); got != 2+flushAttempts {
+		t.Errorf("SaveDynamic calls = %d, want %d", got, 2+flushAttempts)
+	}
+
+	// The failed value is kept for the next flush.
+	src.saveMu.Lock()
+	src.fail, src.batches = nil, nil
+	src.saveMu.Unlock()
+	if err := d.Flush(); err != nil {
+		t.Fatalf("second Flush() error = %v", err)
+	}
+	if want := [][]source.Object{{object("b", "B")}}; !reflect.DeepEqual(src.saved(), want) {
+		t.Errorf("batches = %v, want %v", src.saved(), want)
+	}
+}
+
+func TestDynamicContent_FlushThreshold(t *testing.T) {
+	src := &saveStub{}
+	d := newQueueClient(t, Config{FlushThreshold: 2}, src)
+
+	d.SaveTranslation("en_US", "a", "A")
+	time.Sleep(20 * time.Millisecond)
+	if got := len(src.saved()); got != 0 {
+		t.Fatalf("batches below the threshold = %d, want 0", got)
+	}
+
+	d.SaveTranslation("en_US", "b", "B")
+	deadline := time.Now().Add(time.Second)
+	for len(src.saved()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if want := [][]source.Object{{object("a", "A"), object("b", "B")}}; !reflect.DeepEqual(src.saved(), want) {
+		t.Errorf("batches = %v, want %v", src.saved(), want)
+	}
+}
+
+func TestWriteQueue_KeepsValuesSavedDuringFlush(t *testing.T) {
+	var q writeQueue
+	q.add([]source.Object{object("a", "A"), object("b", "B")})
+	writes := q.writes()
+
+	q.add([]source.Object{object("a", "A2")})
+	q.done(writes)
+
+	got := q.writes()
+	if len(got) != 1 || got[0].object != object("a", "A2") {
+		t.Errorf("writes = %+v, want only a = A2", got)
+	}
+}
+
+// joinedErr is an error type that can't be compared with ==.
+type joinedErr []error
+
+func (e joinedErr) Error() string { return errors.Join(e...).Error() }
+
+func TestFlushError_UnwrapUncomparable(t *testing.T) {
+	failed := joinedErr{errors.New("timeout")}
+	flushErr := &FlushError{Failed: []WriteResult{
+		{Object: object("a", "A"), Err: failed},
+		{Object: object("b", "B"), Err: failed},
+		{Object: object("c", "C"), Err: source.ErrNotFound},
+	}}
+
+	if got := flushErr.Unwrap(); len(got) != 3 {
+		t.Errorf("Unwrap() = %v, want every error of an uncomparable type", got)
+	}
+	if !errors.Is(flushErr, source.ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = false", flushErr)
+	}
+}